@@ -0,0 +1,35 @@
+package action
+
+import (
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	actionLong = templates.LongDesc(`
+		Process the contacts waiting in an App Cloud action service instance as a batch job.
+
+		"action pending" exports the contacts waiting in the action step. Once they are
+		processed, "action complete" moves them on in the campaign and "action error" marks
+		them as errored.`)
+)
+
+func NewCmdAction(f *factory.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "action SUBCOMMAND",
+		Short: "Process the contacts waiting in an action service instance",
+		Long:  actionLong,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdutil.ValidationErrorf("you must specify a subcommand. See 'eloquactl action -h' for help and examples")
+		},
+	}
+
+	// create subcommands
+	cmd.AddCommand(NewCmdActionPending(f))
+	cmd.AddCommand(NewCmdActionSetStatus(f, "complete"))
+	cmd.AddCommand(NewCmdActionSetStatus(f, "error"))
+
+	return cmd
+}
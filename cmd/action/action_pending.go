@@ -0,0 +1,92 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/elqx/eloquactl/cmd/export"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+const (
+	pendingFields = "ContactId:{{Contact.Id}},EmailAddress:{{Contact.Field(C_EmailAddress)}}"
+)
+
+var (
+	actionPendingLong = templates.LongDesc(`
+		Export the contacts waiting in an action service instance.
+
+		Contacts are exported the way "eloquactl export contacts" exports them, filtered on
+		the instance. Unless --fields or --field-set is given, the contact id and email
+		address are exported.`)
+
+	actionPendingExample = templates.Examples(`
+		# Export the contacts waiting in an action step
+		eloquactl action pending --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f -o ndj > pending.ndj
+
+		# Export the contacts of a single execution with more fields
+		eloquactl action pending --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f --execution=1234 \
+		  --fields='EmailAddress:{{Contact.Field(C_EmailAddress)}},Country:{{Contact.Field(C_Country)}}'`)
+)
+
+type ActionPendingOptions struct {
+	*export.ExportContactsOptions
+
+	Instance  string
+	Execution int
+}
+
+func NewCmdActionPending(f *factory.Factory) *cobra.Command {
+	o := &ActionPendingOptions{ExportContactsOptions: export.NewExportContactsOptions(f)}
+
+	cmd := &cobra.Command{
+		Use:     "pending --instance=INSTANCE",
+		Short:   "Export the contacts waiting in an action service instance",
+		Long:    actionPendingLong,
+		Example: actionPendingExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(cmd)
+		},
+	}
+
+	o.ExportFlags.AddFlags(cmd)
+	o.PrintFlags.AddFlags(cmd)
+	cmd.Flags().StringVarP(&o.Instance, "instance", "i", "", "The id of the action service instance.")
+	cmd.Flags().IntVar(&o.Execution, "execution", 0, "The id of the execution the contacts are waiting in. Defaults to any execution of the instance.")
+
+	return cmd
+}
+
+// Complete filters the export on the instance, keeping the --filter given.
+func (o *ActionPendingOptions) Complete() error {
+	if err := cmdutil.ValidateInstance(o.Instance); err != nil {
+		return err
+	}
+
+	if o.Execution < 0 {
+		return errors.New("--execution must not be negative")
+	}
+
+	filter := fmt.Sprintf("EXISTS('%s')", bulkapi.ActionDestination(o.Instance, o.Execution))
+	if *o.ExportFlags.Filter != "" {
+		filter = fmt.Sprintf("%s AND (%s)", filter, *o.ExportFlags.Filter)
+	}
+	*o.ExportFlags.Filter = filter
+
+	if *o.ExportFlags.Fields == "" && *o.ExportFlags.FieldSet == "" {
+		*o.ExportFlags.Fields = pendingFields
+	}
+	return nil
+}
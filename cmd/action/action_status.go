@@ -0,0 +1,186 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+const (
+	contactImportsPath = "/contacts/imports"
+)
+
+// statuses maps the subcommands to the status they set.
+var statuses = map[string]string{
+	"complete": bulkapi.StatusComplete,
+	"error":    bulkapi.StatusErrored,
+}
+
+var (
+	actionSetStatusLong = templates.LongDesc(`
+		Set the status of contacts waiting in an action service instance.
+
+		"complete" moves the contacts on to the next step of the campaign, "error" marks
+		them as errored. The contacts are read from files, CSV with a header row, JSON
+		arrays of objects or newline delimited JSON, e.g. the output of "action pending".
+		They are matched by the email address column, unless --identifier is given; other
+		columns are ignored.
+
+		The result of every row is printed. The exit code is 6 when some rows were rejected.`)
+
+	actionSetStatusExample = templates.Examples(`
+		# Complete the action step for the processed contacts
+		eloquactl action complete --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f -f processed.ndj
+
+		# Mark the contacts that failed processing as errored
+		eloquactl action error --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f -f failed.csv --identifier=ContactId \
+		  --fields='ContactId:{{Contact.Id}}'`)
+)
+
+type ActionSetStatusOptions struct {
+	Client        func() (*bulk.BulkClient, error)
+	Cache         func() (*cache.Cache, error)
+	DryRun        *bool
+	ImportFlags   *cmdutil.ImportFlags
+	FileNameFlags *cmdutil.FileNameFlags
+	PrintFlags    *cmdutil.PrintFlags
+
+	Status    string
+	Instance  string
+	Execution int
+}
+
+func NewActionSetStatusOptions(f *factory.Factory, status string) *ActionSetStatusOptions {
+	return &ActionSetStatusOptions{
+		Client:        f.BulkClient,
+		Cache:         f.Cache,
+		DryRun:        &f.DryRun,
+		ImportFlags:   cmdutil.NewImportFlags(),
+		FileNameFlags: cmdutil.NewFileNameFlags(),
+		PrintFlags:    cmdutil.NewPrintFlags(),
+		Status:        status,
+	}
+}
+
+// NewCmdActionSetStatus returns the command setting the status of the
+// subcommand name, complete or error.
+func NewCmdActionSetStatus(f *factory.Factory, name string) *cobra.Command {
+	o := NewActionSetStatusOptions(f, statuses[name])
+
+	cmd := &cobra.Command{
+		Use:     name + " --instance=INSTANCE -f FILENAME",
+		Short:   fmt.Sprintf("Set the status of contacts waiting in an action service instance to %s", o.Status),
+		Long:    actionSetStatusLong,
+		Example: actionSetStatusExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run()
+		},
+	}
+
+	o.ImportFlags.AddFlags(cmd)
+	o.FileNameFlags.AddFlags(cmd)
+	o.PrintFlags.AddFlags(cmd)
+	cmd.Flags().StringVarP(&o.Instance, "instance", "i", "", "The id of the action service instance.")
+	cmd.Flags().IntVar(&o.Execution, "execution", 0, "The id of the execution the contacts are waiting in. Defaults to any execution of the instance.")
+
+	return cmd
+}
+
+func (o *ActionSetStatusOptions) Validate() error {
+	if err := cmdutil.ValidateInstance(o.Instance); err != nil {
+		return err
+	}
+
+	if o.Execution < 0 {
+		return errors.New("--execution must not be negative")
+	}
+
+	if len(*o.FileNameFlags.FileNames) == 0 {
+		return errors.New("you must specify the contacts with -f")
+	}
+
+	if err := o.ImportFlags.Validate(); err != nil {
+		return err
+	}
+
+	return o.PrintFlags.Validate()
+}
+
+func (o *ActionSetStatusOptions) Run() error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+
+	files, err := o.FileNameFlags.Files(".csv", ".json", ".ndj")
+	if err != nil {
+		return err
+	}
+
+	columns, records, err := cmdutil.ReadRecordFiles(files)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return cmdutil.ValidationErrorf("no contacts to set the status of")
+	}
+
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	c, err := o.Cache()
+	if err != nil {
+		return err
+	}
+
+	md := &bulkapi.Metadata{Client: client, Cache: c}
+	column, statement, err := cmdutil.ContactIdentifier(ctx, md, columns, o.ImportFlags)
+	if err != nil {
+		return err
+	}
+
+	name := *o.ImportFlags.Name
+	if name == "" {
+		name = fmt.Sprintf("eloquactl action %s %s", o.Instance, o.Status)
+	}
+
+	i := &bulkapi.Import{
+		Name:                             name,
+		Fields:                           map[string]string{column: statement},
+		IdentifierFieldName:              column,
+		IsUpdatingMultipleMatchedRecords: *o.ImportFlags.IsUpdatingMultipleMatchedRecords,
+		UpdateRule:                       *o.ImportFlags.UpdateRule,
+		AutoDeleteDuration:               *o.ImportFlags.StagingFlags.AutoDeleteDuration,
+		DataRetentionDuration:            *o.ImportFlags.StagingFlags.DataRetentionDuration,
+		SyncActions: []bulkapi.SyncAction{{
+			Destination: bulkapi.ActionDestination(o.Instance, o.Execution),
+			Action:      bulkapi.SyncActionSetStatus,
+			Status:      o.Status,
+		}},
+	}
+
+	if *o.DryRun {
+		return cmdutil.PrintDryRun("POST", contactImportsPath, i)
+	}
+
+	results, err := cmdutil.RunImport(ctx, client, contactImportsPath, i, records, o.ImportFlags.SyncFlags, *o.ImportFlags.KeepDefinition)
+	if err != nil {
+		return err
+	}
+
+	return cmdutil.PrintImportResults(results, o.PrintFlags, func(imported, total int) string {
+		return fmt.Sprintf("Set the status of %d of %d contacts to %s", imported, total, o.Status)
+	})
+}
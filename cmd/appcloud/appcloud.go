@@ -0,0 +1,43 @@
+package appcloud
+
+import (
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	appCloudLong = templates.LongDesc(`
+		Tools for developing App Cloud apps and services.
+
+		"appcloud apps" and "appcloud services" keep the registration of apps and services
+		in YAML manifests. "appcloud simulate notify" sends records to a service in notify
+		calls, as Eloqua does during a campaign.
+
+		Eloqua signs its calls to App Cloud services with OAuth 1.0a HMAC-SHA1, using the
+		client id and secret of the app. "appcloud sign" signs URLs like Eloqua does, e.g.
+		to call a service under development, and "appcloud verify-signature" checks the
+		signature of a captured call. The client id and secret default to the OAuth2
+		client of the context.`)
+)
+
+func NewCmdAppCloud(f *factory.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "appcloud SUBCOMMAND",
+		Short: "Develop App Cloud apps and services",
+		Long:  appCloudLong,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdutil.ValidationErrorf("you must specify a subcommand. See 'eloquactl appcloud -h' for help and examples")
+		},
+	}
+
+	// create subcommands
+	cmd.AddCommand(NewCmdApps(f))
+	cmd.AddCommand(NewCmdServices(f))
+	cmd.AddCommand(NewCmdSimulate(f))
+	cmd.AddCommand(NewCmdSign(f))
+	cmd.AddCommand(NewCmdVerifySignature(f))
+
+	return cmd
+}
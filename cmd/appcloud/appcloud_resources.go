@@ -0,0 +1,105 @@
+package appcloud
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/elqx/eloquactl/pkg/appcloud"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/restapi"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	resourcesLong = templates.LongDesc(`
+		Manage the registration of App Cloud %[1]s.
+
+		Apps and their feeder, decision, action, content, menu and firehose services are
+		described in YAML manifests, so that their URLs and settings can be kept in git.
+		"get -o yaml" prints the manifests of the registered %[1]s, "apply" registers or
+		updates the %[1]s of manifests, "diff" compares them with Eloqua and "delete"
+		removes %[1]s. Apps and services are matched by name, services within their app.
+
+		kind: App
+		name: Lead Tools
+		enableUrl: https://apps.example.com/enable?install={InstallId}
+		---
+		kind: Service
+		app: Lead Tools
+		type: decision
+		name: Lead Scorer
+		createUrl: https://apps.example.com/lead-scorer/create?instance={InstanceId}
+		notificationUrl: https://apps.example.com/lead-scorer/notify?instance={InstanceId}&execution={ExecutionId}`)
+)
+
+// resource is what the apps and services commands differ in.
+type resource struct {
+	kind     string
+	singular string
+	plural   string
+}
+
+var (
+	appResource     = resource{kind: appcloud.KindApp, singular: "app", plural: "apps"}
+	serviceResource = resource{kind: appcloud.KindService, singular: "service", plural: "services"}
+)
+
+func NewCmdApps(f *factory.Factory) *cobra.Command {
+	return newCmdResources(f, appResource)
+}
+
+func NewCmdServices(f *factory.Factory) *cobra.Command {
+	return newCmdResources(f, serviceResource)
+}
+
+func newCmdResources(f *factory.Factory, r resource) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     r.plural + " SUBCOMMAND",
+		Aliases: []string{r.singular},
+		Short:   fmt.Sprintf("Manage the registration of App Cloud %s", r.plural),
+		Long:    fmt.Sprintf(resourcesLong, r.plural),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdutil.ValidationErrorf("you must specify a subcommand. See 'eloquactl appcloud %s -h' for help and examples", r.plural)
+		},
+	}
+
+	// create subcommands
+	cmd.AddCommand(NewCmdResourcesGet(f, r))
+	cmd.AddCommand(NewCmdResourcesApply(f, r))
+	cmd.AddCommand(NewCmdResourcesDiff(f, r))
+	cmd.AddCommand(NewCmdResourcesDelete(f, r))
+
+	return cmd
+}
+
+// writeManifests writes apps and services as YAML documents that apply
+// takes back.
+func writeManifests(w io.Writer, apps []restapi.App, services []restapi.Service) error {
+	var docs []string
+	for n := range apps {
+		docs = append(docs, "kind: "+appcloud.KindApp+"\n"+appcloud.Render(&apps[n]))
+	}
+	for n := range services {
+		docs = append(docs, "kind: "+appcloud.KindService+"\n"+appcloud.Render(&services[n]))
+	}
+
+	_, err := io.WriteString(w, strings.Join(docs, "---\n"))
+	return err
+}
+
+// readManifests reads the manifests of the -f files.
+func readManifests(f *cmdutil.FileNameFlags) (*appcloud.Manifest, error) {
+	files, err := f.Files(".yaml", ".yml")
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := appcloud.ReadManifests(files)
+	if err != nil {
+		return nil, &cmdutil.ValidationError{Err: err}
+	}
+	return m, nil
+}
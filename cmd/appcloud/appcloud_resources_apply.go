@@ -0,0 +1,101 @@
+package appcloud
+
+import (
+	"fmt"
+
+	"github.com/elqx/eloqua-go/eloqua/rest"
+	"github.com/elqx/eloquactl/pkg/appcloud"
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	resourcesApplyExample = templates.Examples(`
+		# Register or update the apps described in lead-tools.yaml
+		eloquactl appcloud apps apply -f lead-tools.yaml
+
+		# Register or update the services of all manifests of a directory
+		eloquactl appcloud services apply -f appcloud/
+
+		# Show what would change without changing anything
+		eloquactl appcloud services apply -f appcloud/ --dry-run`)
+)
+
+// pastTense of the change actions, as reported.
+var pastTense = map[string]string{
+	appcloud.ActionCreate:    "created",
+	appcloud.ActionUpdate:    "updated",
+	appcloud.ActionUnchanged: "unchanged",
+}
+
+type ResourcesApplyOptions struct {
+	Client        func() (*rest.RestClient, error)
+	DryRun        *bool
+	FileNameFlags *cmdutil.FileNameFlags
+
+	resource resource
+}
+
+func NewCmdResourcesApply(f *factory.Factory, r resource) *cobra.Command {
+	o := &ResourcesApplyOptions{
+		Client:        f.RestClient,
+		DryRun:        &f.DryRun,
+		FileNameFlags: cmdutil.NewFileNameFlags(),
+		resource:      r,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "apply -f FILENAME",
+		Short:   fmt.Sprintf("Register or update the %s described in manifests", r.plural),
+		Example: resourcesApplyExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(*o.FileNameFlags.FileNames) == 0 {
+				return cmdutil.ValidationErrorf("you must specify the manifests with -f")
+			}
+
+			return o.Run()
+		},
+	}
+
+	o.FileNameFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+func (o *ResourcesApplyOptions) Run() error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+
+	m, err := readManifests(o.FileNameFlags)
+	if err != nil {
+		return err
+	}
+
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	remote, err := appcloud.FetchRemote(ctx, client, o.resource.kind == appcloud.KindService)
+	if err != nil {
+		return err
+	}
+
+	changes := appcloud.Plan(m, remote, o.resource.kind)
+	if len(changes) == 0 {
+		return cmdutil.ValidationErrorf("there are no %s in the manifests", o.resource.plural)
+	}
+
+	if *o.DryRun {
+		for _, c := range changes {
+			fmt.Printf("%s %q %s (dry run)\n", o.resource.singular, c.Name, pastTense[c.Action])
+		}
+		return nil
+	}
+
+	return appcloud.Apply(ctx, client, changes, remote, func(c appcloud.Change) {
+		fmt.Printf("%s %q %s\n", o.resource.singular, c.Name, pastTense[c.Action])
+	})
+}
@@ -0,0 +1,216 @@
+package appcloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elqx/eloqua-go/eloqua/rest"
+	"github.com/elqx/eloquactl/pkg/appcloud"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/restapi"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	resourcesDeleteLong = templates.LongDesc(`
+		Delete registered %[1]s, given by name or by manifests.
+
+		The %[1]s to delete are printed first. Deleting an app deletes its services too,
+		so apps are only deleted when the deletion is confirmed, or with --yes when
+		stdin is not a terminal. With --dry-run nothing is deleted.`)
+
+	resourcesDeleteExample = templates.Examples(`
+		# Delete an app, with its services, without asking
+		eloquactl appcloud apps delete 'Lead Tools' --yes
+
+		# Delete a service of an app
+		eloquactl appcloud services delete 'Lead Scorer' --app='Lead Tools'
+
+		# Delete the services described in a manifest
+		eloquactl appcloud services delete -f lead-tools.yaml
+
+		# Show what would be deleted without deleting anything
+		eloquactl appcloud apps delete -f lead-tools.yaml --dry-run`)
+)
+
+type ResourcesDeleteOptions struct {
+	Client        func() (*rest.RestClient, error)
+	DryRun        *bool
+	FileNameFlags *cmdutil.FileNameFlags
+
+	resource resource
+	App      string
+	Names    []string
+	Yes      bool
+}
+
+func NewCmdResourcesDelete(f *factory.Factory, r resource) *cobra.Command {
+	o := &ResourcesDeleteOptions{
+		Client:        f.RestClient,
+		DryRun:        &f.DryRun,
+		FileNameFlags: cmdutil.NewFileNameFlags(),
+		resource:      r,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "delete (NAME... | -f FILENAME)",
+		Aliases: []string{"rm"},
+		Short:   fmt.Sprintf("Delete registered %s", r.plural),
+		Long:    fmt.Sprintf(resourcesDeleteLong, r.plural),
+		Example: resourcesDeleteExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Names = args
+			if (len(o.Names) == 0) == (len(*o.FileNameFlags.FileNames) == 0) {
+				return cmdutil.ValidationErrorf("you must specify the %s to delete by name or with -f", r.plural)
+			}
+
+			return o.Run()
+		},
+	}
+
+	o.FileNameFlags.AddFlags(cmd)
+	if r.kind == appcloud.KindService {
+		cmd.Flags().StringVar(&o.App, "app", "", "The name of the app of the services. Required when services of several apps have the name.")
+	} else {
+		cmd.Flags().BoolVarP(&o.Yes, "yes", "y", false, "If true, the apps are deleted with their services without asking.")
+	}
+
+	return cmd
+}
+
+func (o *ResourcesDeleteOptions) Run() error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+
+	var m *appcloud.Manifest
+	if len(o.Names) == 0 {
+		var err error
+		if m, err = readManifests(o.FileNameFlags); err != nil {
+			return err
+		}
+	}
+
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	// the services of the apps are listed in the plan, they are deleted too
+	remote, err := appcloud.FetchRemote(ctx, client, true)
+	if err != nil {
+		return err
+	}
+
+	if o.resource.kind == appcloud.KindApp {
+		return o.deleteApps(ctx, client, remote, m)
+	}
+	return o.deleteServices(ctx, client, remote, m)
+}
+
+func (o *ResourcesDeleteOptions) deleteApps(ctx context.Context, client *rest.RestClient, remote *appcloud.Remote, m *appcloud.Manifest) error {
+	names := o.Names
+	if m != nil {
+		for _, a := range m.Apps {
+			names = append(names, a.Name)
+		}
+	}
+
+	var apps []*restapi.App
+	for _, name := range names {
+		a := remote.App(name)
+		if a == nil {
+			return fmt.Errorf("app %q not found", name)
+		}
+		apps = append(apps, a)
+	}
+
+	for _, a := range apps {
+		fmt.Printf("app %q will be deleted\n", a.Name)
+		for n := range remote.Services {
+			if s := &remote.Services[n]; s.AppID == a.ID {
+				fmt.Printf("service %q will be deleted\n", appcloud.ServiceKey(s))
+			}
+		}
+	}
+
+	if *o.DryRun {
+		return nil
+	}
+
+	if !o.Yes {
+		ok, err := cmdutil.Confirm(fmt.Sprintf("Delete %d apps with their services?", len(apps)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return cmdutil.ValidationErrorf("the apps were not deleted, confirm the deletion or set --yes")
+		}
+	}
+
+	for _, a := range apps {
+		if err := restapi.DeleteApp(ctx, client, a.ID); err != nil {
+			return fmt.Errorf("failed to delete app %q: %w", a.Name, err)
+		}
+		fmt.Printf("app %q deleted\n", a.Name)
+	}
+	return nil
+}
+
+func (o *ResourcesDeleteOptions) deleteServices(ctx context.Context, client *rest.RestClient, remote *appcloud.Remote, m *appcloud.Manifest) error {
+	var services []*restapi.Service
+	if m != nil {
+		for n := range m.Services {
+			key := appcloud.ServiceKey(&m.Services[n])
+			s := remote.Service(key)
+			if s == nil {
+				return fmt.Errorf("service %q not found", key)
+			}
+			services = append(services, s)
+		}
+	}
+	for _, name := range o.Names {
+		s, err := o.service(remote, name)
+		if err != nil {
+			return err
+		}
+		services = append(services, s)
+	}
+
+	for _, s := range services {
+		fmt.Printf("service %q will be deleted\n", appcloud.ServiceKey(s))
+	}
+
+	if *o.DryRun {
+		return nil
+	}
+
+	for _, s := range services {
+		if err := restapi.DeleteService(ctx, client, s.AppID, s.ID); err != nil {
+			return fmt.Errorf("failed to delete service %q: %w", appcloud.ServiceKey(s), err)
+		}
+		fmt.Printf("service %q deleted\n", appcloud.ServiceKey(s))
+	}
+	return nil
+}
+
+// service returns the service with the given name, of --app if it is given.
+func (o *ResourcesDeleteOptions) service(remote *appcloud.Remote, name string) (*restapi.Service, error) {
+	var found *restapi.Service
+	for n := range remote.Services {
+		s := &remote.Services[n]
+		if s.Name != name || o.App != "" && s.App != o.App {
+			continue
+		}
+		if found != nil {
+			return nil, cmdutil.ValidationErrorf("services of apps %q and %q are named %q, set the app with --app", found.App, s.App, name)
+		}
+		found = s
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("service %q not found", name)
+	}
+	return found, nil
+}
@@ -0,0 +1,101 @@
+package appcloud
+
+import (
+	"fmt"
+
+	"github.com/elqx/eloqua-go/eloqua/rest"
+	"github.com/elqx/eloquactl/pkg/appcloud"
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	resourcesDiffLong = templates.LongDesc(`
+		Compare the manifests with the registration in Eloqua.
+
+		The differences are printed for every app or service of the manifests that apply
+		would create or update, lines prefixed with - as registered in Eloqua and with +
+		as described in the manifests. The exit code is 1 when there are differences.
+		Only the fields set in the manifests are compared, those left out keep the values
+		Eloqua has.`)
+
+	resourcesDiffExample = templates.Examples(`
+		# Compare the services of the manifests of a directory with Eloqua
+		eloquactl appcloud services diff -f appcloud/`)
+)
+
+type ResourcesDiffOptions struct {
+	Client        func() (*rest.RestClient, error)
+	FileNameFlags *cmdutil.FileNameFlags
+
+	resource resource
+}
+
+func NewCmdResourcesDiff(f *factory.Factory, r resource) *cobra.Command {
+	o := &ResourcesDiffOptions{
+		Client:        f.RestClient,
+		FileNameFlags: cmdutil.NewFileNameFlags(),
+		resource:      r,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "diff -f FILENAME",
+		Short:   fmt.Sprintf("Compare the %s described in manifests with Eloqua", r.plural),
+		Long:    resourcesDiffLong,
+		Example: resourcesDiffExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(*o.FileNameFlags.FileNames) == 0 {
+				return cmdutil.ValidationErrorf("you must specify the manifests with -f")
+			}
+
+			return o.Run()
+		},
+	}
+
+	o.FileNameFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+func (o *ResourcesDiffOptions) Run() error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+
+	m, err := readManifests(o.FileNameFlags)
+	if err != nil {
+		return err
+	}
+
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	remote, err := appcloud.FetchRemote(ctx, client, o.resource.kind == appcloud.KindService)
+	if err != nil {
+		return err
+	}
+
+	differ := 0
+	for _, c := range appcloud.Plan(m, remote, o.resource.kind) {
+		if c.Action == appcloud.ActionUnchanged {
+			continue
+		}
+		differ++
+
+		from := fmt.Sprintf("eloqua %s %q", o.resource.singular, c.Name)
+		if c.Action == appcloud.ActionCreate {
+			from = "/dev/null"
+		}
+		fmt.Printf("--- %s\n+++ manifest %s %q\n", from, o.resource.singular, c.Name)
+		local, remote := appcloud.Compared(c.Local, c.Remote)
+		fmt.Print(cmdutil.Diff(remote, local))
+	}
+
+	if differ > 0 {
+		return fmt.Errorf("%d %s differ from Eloqua", differ, o.resource.plural)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package appcloud
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/elqx/eloqua-go/eloqua/rest"
+	"github.com/elqx/eloquactl/pkg/appcloud"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/printers"
+	"github.com/elqx/eloquactl/pkg/restapi"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	resourcesGetExample = templates.Examples(`
+		# List the apps
+		eloquactl appcloud apps get
+
+		# Save the manifest of an app
+		eloquactl appcloud apps get 'Lead Tools' -o yaml > lead-tools.yaml
+
+		# List the services of an app
+		eloquactl appcloud services get --app='Lead Tools'`)
+)
+
+type ResourcesGetOptions struct {
+	Client     func() (*rest.RestClient, error)
+	PrintFlags *cmdutil.PrintFlags
+
+	resource resource
+	App      string
+	Names    []string
+}
+
+func NewCmdResourcesGet(f *factory.Factory, r resource) *cobra.Command {
+	o := &ResourcesGetOptions{
+		Client:     f.RestClient,
+		PrintFlags: cmdutil.NewPrintFlags(),
+		resource:   r,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "get [NAME]...",
+		Aliases: []string{"list", "ls"},
+		Short:   fmt.Sprintf("List the registered %s, -o yaml prints their manifests", r.plural),
+		Example: resourcesGetExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Names = args
+			if err := o.PrintFlags.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run()
+		},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+	if r.kind == appcloud.KindService {
+		cmd.Flags().StringVar(&o.App, "app", "", "The name of the app whose services are listed. Defaults to all apps.")
+	}
+
+	return cmd
+}
+
+func (o *ResourcesGetOptions) Run() error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	remote, err := appcloud.FetchRemote(ctx, client, o.resource.kind == appcloud.KindService)
+	if err != nil {
+		return err
+	}
+
+	var apps []restapi.App
+	var services []restapi.Service
+	found := map[string]bool{}
+	if o.resource.kind == appcloud.KindApp {
+		for _, a := range remote.Apps {
+			if o.selected(a.Name) {
+				found[a.Name] = true
+				apps = append(apps, a)
+			}
+		}
+	} else {
+		if o.App != "" && remote.App(o.App) == nil {
+			return fmt.Errorf("app %q not found", o.App)
+		}
+		for _, s := range remote.Services {
+			if (o.App == "" || s.App == o.App) && o.selected(s.Name) {
+				found[s.Name] = true
+				services = append(services, s)
+			}
+		}
+	}
+
+	for _, name := range o.Names {
+		if !found[name] {
+			return fmt.Errorf("%s %q not found", o.resource.singular, name)
+		}
+	}
+
+	if strings.EqualFold(*o.PrintFlags.OutputFormat, "yaml") {
+		return writeManifests(os.Stdout, apps, services)
+	}
+
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	w := printers.NewTabWriter(os.Stdout)
+	defer w.Flush()
+
+	if o.resource.kind == appcloud.KindApp {
+		return printer.PrintResource(apps, w)
+	}
+	return printer.PrintResource(services, w)
+}
+
+func (o *ResourcesGetOptions) selected(name string) bool {
+	if len(o.Names) == 0 {
+		return true
+	}
+	for _, n := range o.Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
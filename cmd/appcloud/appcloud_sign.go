@@ -0,0 +1,95 @@
+package appcloud
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/oauth1"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	signLong = templates.LongDesc(`
+		Sign a URL as Eloqua signs its App Cloud calls.
+
+		The oauth_ parameters and the HMAC-SHA1 signature are added to the query of the
+		URL, which is printed. The signature is valid for the given method only and, as
+		services should reject old timestamps and used nonces, for a single call made
+		within minutes.`)
+
+	signExample = templates.Examples(`
+		# Sign a notify call to a decision service served locally
+		eloquactl appcloud sign 'http://localhost:8080/lead-scorer/notify?instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f&execution=1234'
+
+		# Call the create endpoint of a service with the client of another app
+		curl -X POST "$(eloquactl appcloud sign --client-id=ID --client-secret=SECRET \
+		  'http://localhost:8080/lead-scorer/create?instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f')"`)
+)
+
+type SignOptions struct {
+	AppCredentials func() (string, string, error)
+	AppFlags       *cmdutil.AppFlags
+
+	Method string
+	URL    string
+}
+
+func NewSignOptions(f *factory.Factory) *SignOptions {
+	return &SignOptions{
+		AppCredentials: f.AppCredentials,
+		AppFlags:       cmdutil.NewAppFlags(),
+	}
+}
+
+func NewCmdSign(f *factory.Factory) *cobra.Command {
+	o := NewSignOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "sign URL",
+		Short:   "Sign a URL as Eloqua signs App Cloud calls",
+		Long:    signLong,
+		Example: signExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.URL = args[0]
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run()
+		},
+	}
+
+	o.AppFlags.AddFlags(cmd)
+	cmd.Flags().StringVarP(&o.Method, "method", "X", "POST", "The HTTP method of the call.")
+
+	return cmd
+}
+
+func (o *SignOptions) Validate() error {
+	if o.Method == "" {
+		return errors.New("--method must not be empty")
+	}
+	o.Method = strings.ToUpper(o.Method)
+
+	return o.AppFlags.Validate()
+}
+
+func (o *SignOptions) Run() error {
+	id, secret, err := o.AppFlags.Credentials(o.AppCredentials)
+	if err != nil {
+		return err
+	}
+
+	signed, err := oauth1.NewSigner(id, secret).SignURL(o.Method, o.URL)
+	if err != nil {
+		return &cmdutil.ValidationError{Err: err}
+	}
+
+	fmt.Println(signed)
+	return nil
+}
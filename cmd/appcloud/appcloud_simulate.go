@@ -0,0 +1,214 @@
+package appcloud
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/oauth1"
+	"github.com/elqx/eloquactl/pkg/printers"
+	"github.com/elqx/eloquactl/pkg/simulator"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	simulateNotifyLong = templates.LongDesc(`
+		Send notify calls to a service as Eloqua does, without a campaign.
+
+		The records, CSV with a header row, JSON arrays of objects or newline delimited
+		JSON, are sent in batches of --batch-size records, with the offset, limit,
+		totalResults, count and hasMore of Eloqua notifications. All batches are of the
+		same execution, a random one unless --execution is given.
+
+		The {InstanceId} and {ExecutionId} templates of the URL are replaced, the instance
+		and execution query parameters are added to URLs without templates. Calls are
+		signed with the client id and secret of the app, unless --insecure is given.
+
+		A line is printed for every call. Use -o ndj to record the responses, bodies
+		included. The exit code is 6 when some calls failed.`)
+
+	simulateNotifyExample = templates.Examples(`
+		# Send contacts to a decision service served locally
+		eloquactl appcloud simulate notify --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f \
+		  --records=contacts.ndjson --url=http://localhost:8080/lead-scorer/notify
+
+		# Page 250 records by 100 and record the responses
+		eloquactl appcloud simulate notify --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f \
+		  --records=contacts.csv --batch-size=100 -o ndj \
+		  --url='http://localhost:8080/lead-scorer/notify?instance={InstanceId}&execution={ExecutionId}' > responses.ndjson`)
+)
+
+func NewCmdSimulate(f *factory.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate SUBCOMMAND",
+		Short: "Simulate the calls of Eloqua to App Cloud services",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdutil.ValidationErrorf("you must specify a subcommand. See 'eloquactl appcloud simulate -h' for help and examples")
+		},
+	}
+
+	cmd.AddCommand(NewCmdSimulateNotify(f))
+
+	return cmd
+}
+
+type SimulateNotifyOptions struct {
+	AppCredentials func() (string, string, error)
+	AppFlags       *cmdutil.AppFlags
+	PrintFlags     *cmdutil.PrintFlags
+
+	URL       string
+	Instance  string
+	Execution int
+	Records   string
+	BatchSize int
+	Insecure  bool
+}
+
+func NewSimulateNotifyOptions(f *factory.Factory) *SimulateNotifyOptions {
+	return &SimulateNotifyOptions{
+		AppCredentials: f.AppCredentials,
+		AppFlags:       cmdutil.NewAppFlags(),
+		PrintFlags:     cmdutil.NewPrintFlags(),
+		BatchSize:      simulator.DefaultBatchSize,
+	}
+}
+
+func NewCmdSimulateNotify(f *factory.Factory) *cobra.Command {
+	o := NewSimulateNotifyOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "notify --instance=INSTANCE --records=FILENAME --url=URL",
+		Short:   "Send records to a service in notify calls",
+		Long:    simulateNotifyLong,
+		Example: simulateNotifyExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run()
+		},
+	}
+
+	o.AppFlags.AddFlags(cmd)
+	o.PrintFlags.AddFlags(cmd)
+	cmd.Flags().StringVar(&o.URL, "url", "", "The notification URL of the service.")
+	cmd.Flags().StringVarP(&o.Instance, "instance", "i", "", "The id of the service instance.")
+	cmd.Flags().IntVar(&o.Execution, "execution", 0, "The id of the execution. Defaults to a random one.")
+	cmd.Flags().StringVar(&o.Records, "records", "", "The file with the records to send, - for stdin.")
+	cmd.Flags().IntVar(&o.BatchSize, "batch-size", o.BatchSize, "The number of records per call, as maxRecordsPerNotification.")
+	cmd.Flags().BoolVar(&o.Insecure, "insecure", false, "Don't sign the calls.")
+
+	return cmd
+}
+
+func (o *SimulateNotifyOptions) Validate() error {
+	if o.URL == "" {
+		return errors.New("--url is required")
+	}
+
+	if err := cmdutil.ValidateInstance(o.Instance); err != nil {
+		return err
+	}
+
+	if o.Execution < 0 {
+		return errors.New("--execution must not be negative")
+	}
+
+	if o.Records == "" {
+		return errors.New("--records is required")
+	}
+
+	if o.BatchSize < 1 || o.BatchSize > simulator.MaxBatchSize {
+		return fmt.Errorf("--batch-size must be between 1 and %d", simulator.MaxBatchSize)
+	}
+
+	if o.Insecure && *o.AppFlags.ClientID != "" {
+		return errors.New("--insecure and --client-id are mutually exclusive")
+	}
+
+	if err := o.AppFlags.Validate(); err != nil {
+		return err
+	}
+	return o.PrintFlags.Validate()
+}
+
+func (o *SimulateNotifyOptions) Run() error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+
+	_, records, err := cmdutil.ReadRecords(o.Records)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return cmdutil.ValidationErrorf("%s has no records", o.Records)
+	}
+
+	if o.Execution == 0 {
+		o.Execution = rand.New(rand.NewSource(time.Now().UnixNano())).Intn(1000000) + 1
+	}
+
+	s := &simulator.Simulator{URL: o.URL, Instance: o.Instance, Execution: o.Execution}
+	if !o.Insecure {
+		id, secret, err := o.AppFlags.Credentials(o.AppCredentials)
+		if err != nil {
+			return err
+		}
+		s.Signer = oauth1.NewSigner(id, secret)
+	}
+
+	notifyURL, err := s.NotifyURL()
+	if err != nil {
+		return &cmdutil.ValidationError{Err: err}
+	}
+
+	batches := simulator.Batches(records, o.BatchSize)
+	fmt.Fprintf(os.Stderr, "Sending %d records in %d calls to %s\n", len(records), len(batches), notifyURL)
+
+	var results []simulator.Result
+	failed := 0
+	for n := range batches {
+		if ctx.Err() != nil {
+			break
+		}
+
+		r, err := s.Notify(ctx, &batches[n])
+		if err != nil {
+			return err
+		}
+		if r.Failed() {
+			failed++
+		}
+		results = append(results, *r)
+	}
+
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	w := printers.NewTabWriter(os.Stdout)
+	if err := printer.PrintResource(results, w); err != nil {
+		return err
+	}
+	w.Flush()
+
+	switch {
+	case ctx.Err() != nil:
+		return &cmdutil.InterruptedError{}
+	case failed == 1 && len(batches) == 1:
+		return errors.New("the notify call failed")
+	case failed == len(batches):
+		return fmt.Errorf("all %d notify calls failed", failed)
+	case failed > 0:
+		return &cmdutil.PartialError{Err: fmt.Errorf("%d of %d notify calls failed", failed, len(batches))}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package appcloud
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/oauth1"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifySignatureLong = templates.LongDesc(`
+		Check the OAuth 1.0a signature of a captured App Cloud call.
+
+		The URL is the one the call was sent to, with its query. The oauth_ parameters
+		are taken from the query and from the Authorization header given with
+		--authorization. Use the public URL of the service, the signature covers the
+		scheme, host and path Eloqua called.
+
+		The timestamp of the call is not checked unless --max-skew is given, as captured
+		calls are usually old. The exit code is 1 when the signature is invalid.`)
+
+	verifySignatureExample = templates.Examples(`
+		# Check a notify call found in the access log of a service
+		eloquactl appcloud verify-signature 'https://apps.example.com/lead-scorer/notify?instance=...&oauth_signature=...'
+
+		# Check a call signed in the Authorization header, and print the signature base string
+		eloquactl appcloud verify-signature -X GET --authorization='OAuth oauth_consumer_key="...", ...' \
+		  https://apps.example.com/lead-scorer/configure?instance=... --show-base`)
+)
+
+type VerifySignatureOptions struct {
+	AppCredentials func() (string, string, error)
+	AppFlags       *cmdutil.AppFlags
+
+	Method        string
+	Authorization string
+	MaxSkew       time.Duration
+	ShowBase      bool
+	URL           string
+}
+
+func NewVerifySignatureOptions(f *factory.Factory) *VerifySignatureOptions {
+	return &VerifySignatureOptions{
+		AppCredentials: f.AppCredentials,
+		AppFlags:       cmdutil.NewAppFlags(),
+	}
+}
+
+func NewCmdVerifySignature(f *factory.Factory) *cobra.Command {
+	o := NewVerifySignatureOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "verify-signature URL",
+		Short:   "Check the signature of a captured App Cloud call",
+		Long:    verifySignatureLong,
+		Example: verifySignatureExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.URL = args[0]
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run()
+		},
+	}
+
+	o.AppFlags.AddFlags(cmd)
+	cmd.Flags().StringVarP(&o.Method, "method", "X", "POST", "The HTTP method of the call.")
+	cmd.Flags().StringVar(&o.Authorization, "authorization", "", "The Authorization header of the call, if it was signed in the header.")
+	cmd.Flags().DurationVar(&o.MaxSkew, "max-skew", 0, "How old the call may be, e.g. 5m. Zero skips the timestamp check.")
+	cmd.Flags().BoolVar(&o.ShowBase, "show-base", false, "If true, print the signature base string and the expected signature to stderr.")
+
+	return cmd
+}
+
+func (o *VerifySignatureOptions) Validate() error {
+	if o.Method == "" {
+		return errors.New("--method must not be empty")
+	}
+	o.Method = strings.ToUpper(o.Method)
+
+	if o.MaxSkew < 0 {
+		return errors.New("--max-skew must not be negative")
+	}
+
+	return o.AppFlags.Validate()
+}
+
+func (o *VerifySignatureOptions) Run() error {
+	id, secret, err := o.AppFlags.Credentials(o.AppCredentials)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(o.Method, o.URL, nil)
+	if err != nil {
+		return &cmdutil.ValidationError{Err: err}
+	}
+	if o.Authorization != "" {
+		req.Header.Set("Authorization", o.Authorization)
+	}
+
+	params, err := oauth1.RequestParams(req)
+	if err != nil {
+		return &cmdutil.ValidationError{Err: err}
+	}
+
+	if o.ShowBase {
+		base, err := oauth1.SignatureBase(o.Method, o.URL, params)
+		if err != nil {
+			return &cmdutil.ValidationError{Err: err}
+		}
+		fmt.Fprintf(os.Stderr, "Signature base string: %s\n", base)
+		fmt.Fprintf(os.Stderr, "Expected signature:    %s\n", oauth1.Signature(base, secret, ""))
+	}
+
+	v := oauth1.NewVerifier(id, secret)
+	v.MaxSkew = o.MaxSkew
+	if err := v.Verify(o.Method, o.URL, params); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, "The signature is valid.")
+	return nil
+}
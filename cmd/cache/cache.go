@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/config"
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cacheLong = templates.LongDesc(`
+		Inspect or clear the metadata cache of a context.
+
+		The fields of contacts, accounts, activities and custom objects, the ids of
+		custom objects and the lead scoring models rarely change, so get and export
+		commands keep them in a file per context for --cache-ttl (24h by default).
+		Use --refresh-cache on any command to fetch them again, e.g. after adding a field.`)
+)
+
+func NewCmdCache(f *factory.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache SUBCOMMAND",
+		Short: "Inspect or clear the metadata cache",
+		Long:  cacheLong,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdutil.ValidationErrorf("you must specify a subcommand. See 'eloquactl cache -h' for help and examples")
+		},
+	}
+
+	// create subcommands
+	cmd.AddCommand(NewCmdCacheShow(f))
+	cmd.AddCommand(NewCmdCacheClear(f))
+
+	return cmd
+}
+
+// contextCache returns the cache of the active context, also when caching
+// is disabled with --cache-ttl=0.
+func contextCache(f *factory.Factory) (*cache.Cache, string, error) {
+	c, _, err := f.Context()
+	if err != nil {
+		return nil, "", err
+	}
+
+	path, err := config.MetadataCachePath(c.Name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return cache.New(path, f.CacheTTL, false), c.Name, nil
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/elqx/eloquactl/pkg/config"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cacheClearExample = templates.Examples(`
+		# Clear the cached metadata of the current context
+		eloquactl cache clear
+
+		# Clear the cached metadata of all contexts
+		eloquactl cache clear --all`)
+)
+
+type CacheClearOptions struct {
+	Factory *factory.Factory
+
+	All bool
+}
+
+func NewCmdCacheClear(f *factory.Factory) *cobra.Command {
+	o := &CacheClearOptions{Factory: f}
+
+	cmd := &cobra.Command{
+		Use:     "clear",
+		Short:   "Clear the cached metadata of a context",
+		Example: cacheClearExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.All, "all", false, "If true, clear the cached metadata of all contexts.")
+
+	return cmd
+}
+
+func (o *CacheClearOptions) Run() error {
+	if o.All {
+		dir, err := config.Dir()
+		if err != nil {
+			return err
+		}
+
+		if err := os.RemoveAll(filepath.Join(dir, "cache")); err != nil {
+			return err
+		}
+		fmt.Println("Cleared the metadata cache of all contexts.")
+		return nil
+	}
+
+	c, name, err := contextCache(o.Factory)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Clear(); err != nil {
+		return err
+	}
+	fmt.Printf("Cleared the metadata cache of context %q.\n", name)
+	return nil
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/printers"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cacheShowExample = templates.Examples(`
+		# Show what is cached for the current context
+		eloquactl cache show
+
+		# Show what is cached for the sandbox context
+		eloquactl cache show --context=sandbox`)
+)
+
+type CacheShowOptions struct {
+	Factory    *factory.Factory
+	PrintFlags *cmdutil.PrintFlags
+}
+
+func NewCacheShowOptions(f *factory.Factory) *CacheShowOptions {
+	return &CacheShowOptions{
+		Factory:    f,
+		PrintFlags: cmdutil.NewPrintFlags(),
+	}
+}
+
+func NewCmdCacheShow(f *factory.Factory) *cobra.Command {
+	o := NewCacheShowOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "show",
+		Aliases: []string{"list", "ls"},
+		Short:   "Show the cached metadata of a context",
+		Example: cacheShowExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.PrintFlags.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run()
+		},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+func (o *CacheShowOptions) Run() error {
+	c, name, err := contextCache(o.Factory)
+	if err != nil {
+		return err
+	}
+
+	entries := c.Entries()
+	if len(entries) == 0 {
+		fmt.Fprintf(os.Stderr, "No metadata cached for context %q.\n", name)
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "Cache file: %s\n", c.Path)
+
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	w := printers.NewTabWriter(os.Stdout)
+	defer w.Flush()
+
+	return printer.PrintResource(entries, w)
+}
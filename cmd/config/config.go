@@ -0,0 +1,35 @@
+package config
+
+import (
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configLong = templates.LongDesc(`
+		Modify eloquactl config file using subcommands like "eloquactl config use-context sandbox".
+
+		The config file holds a number of named contexts. Each context points to an Eloqua
+		instance: company, user, the credentials to use and the base URLs of the APIs.`)
+)
+
+func NewCmdConfig() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config SUBCOMMAND",
+		Short: "Modify eloquactl config file",
+		Long:  configLong,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdutil.ValidationErrorf("you must specify a subcommand. See 'eloquactl config -h' for help and examples")
+		},
+	}
+
+	// create subcommands
+	cmd.AddCommand(NewCmdConfigGetContexts())
+	cmd.AddCommand(NewCmdConfigUseContext())
+	cmd.AddCommand(NewCmdConfigSetContext())
+	cmd.AddCommand(NewCmdConfigSetCredentials())
+	cmd.AddCommand(NewCmdConfigView())
+
+	return cmd
+}
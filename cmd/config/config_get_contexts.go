@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/elqx/eloquactl/pkg/config"
+	"github.com/elqx/eloquactl/pkg/printers"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	getContextsExample = templates.Examples(`
+		# List all the contexts in the config file
+		eloquactl config get-contexts`)
+)
+
+type GetContextsOptions struct {
+	NoHeaders bool
+}
+
+func NewCmdConfigGetContexts() *cobra.Command {
+	o := &GetContextsOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "get-contexts",
+		Short:   "Describe one or many contexts",
+		Example: getContextsExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(args)
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.NoHeaders, "no-headers", false, "Don't print headers (default print headers).")
+
+	return cmd
+}
+
+func (o *GetContextsOptions) Run(args []string) error {
+	c, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	w := printers.NewTabWriter(os.Stdout)
+	defer w.Flush()
+
+	if !o.NoHeaders {
+		fmt.Fprintln(w, "CURRENT\tNAME\tCOMPANY\tUSER\tCREDENTIALS")
+	}
+
+	for _, nc := range c.Contexts {
+		if len(args) > 0 && !contains(args, nc.Name) {
+			continue
+		}
+
+		current := ""
+		if nc.Name == c.CurrentContext {
+			current = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", current, nc.Name, nc.Context.Company, nc.Context.User, nc.Context.Credentials)
+	}
+
+	return nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/elqx/eloquactl/pkg/config"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	setContextLong = templates.LongDesc(`
+		Set a context entry in the config file.
+
+		Specifying a name that already exists will merge new fields on top of existing values for those fields.`)
+
+	setContextExample = templates.Examples(`
+		# Add a context for the sandbox instance
+		eloquactl config set-context sandbox --company=AcmeSandbox --user=jdoe --credentials=sandbox \
+		  --bulk-url='https://secure.p03.eloqua.com/API/Bulk/{version}/' \
+		  --rest-url='https://secure.p03.eloqua.com/API/REST/{version}/'
+
+		# Change the user of the prod context
+		eloquactl config set-context prod --user=jsmith`)
+)
+
+type SetContextOptions struct {
+	Company     string
+	User        string
+	Credentials string
+	BulkURL     string
+	RestURL     string
+
+	Current bool
+}
+
+func NewCmdConfigSetContext() *cobra.Command {
+	o := &SetContextOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "set-context CONTEXT_NAME",
+		Short:   "Set a context entry in the config file",
+		Long:    setContextLong,
+		Example: setContextExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return cmdutil.ValidationErrorf("set-context needs exactly one context name")
+			}
+
+			return o.Run(cmd, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Company, "company", "", "Eloqua company (site) name.")
+	cmd.Flags().StringVar(&o.User, "user", "", "Eloqua user name.")
+	cmd.Flags().StringVar(&o.Credentials, "credentials", "", "Name of the credentials entry used to authenticate the user.")
+	cmd.Flags().StringVar(&o.BulkURL, "bulk-url", "", "Bulk API base URL, may contain a {version} placeholder.")
+	cmd.Flags().StringVar(&o.RestURL, "rest-url", "", "REST API base URL, may contain a {version} placeholder.")
+	cmd.Flags().BoolVar(&o.Current, "current", false, "Make the context the current one.")
+
+	return cmd
+}
+
+func (o *SetContextOptions) Run(cmd *cobra.Command, name string) error {
+	c, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	ctx := config.Context{}
+	if existing, err := c.Context(name); err == nil {
+		ctx = *existing
+	}
+
+	if cmd.Flags().Changed("company") {
+		ctx.Company = o.Company
+	}
+	if cmd.Flags().Changed("user") {
+		ctx.User = o.User
+	}
+	if cmd.Flags().Changed("credentials") {
+		ctx.Credentials = o.Credentials
+	}
+	if cmd.Flags().Changed("bulk-url") {
+		ctx.BulkURL = o.BulkURL
+	}
+	if cmd.Flags().Changed("rest-url") {
+		ctx.RestURL = o.RestURL
+	}
+
+	c.SetContext(name, ctx)
+	if o.Current || c.CurrentContext == "" {
+		c.CurrentContext = name
+	}
+
+	if err := c.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Context %q set.\n", name)
+	return nil
+}
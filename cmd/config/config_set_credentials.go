@@ -0,0 +1,211 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/elqx/eloquactl/pkg/config"
+	"github.com/elqx/eloquactl/pkg/credentials"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	setCredentialsLong = templates.LongDesc(`
+		Set a credentials entry in the config file.
+
+		Specifying a name that already exists will merge new fields on top of existing values.
+
+		With the oauth2 auth provider, the first command using the credentials obtains a token,
+		either by the password grant or by the authorization code grant which opens a local
+		listener on the redirect URL. Tokens are cached per context and refreshed automatically.
+
+		Secrets can be kept out of the config file with --source:
+		  inline   in the config file (default)
+		  env      in environment variables, ELOQUACTL_PASSWORD and ELOQUACTL_CLIENT_SECRET by default
+		  keyring  in the OS keyring, through the Secret Service API (requires secret-tool)
+		  file     in a file encrypted with a passphrase, taken from ELOQUACTL_PASSPHRASE or prompted for
+		  exec     printed as JSON by a credential helper command, e.g. {"password": "..."}`)
+
+	setCredentialsExample = templates.Examples(`
+		# Use basic auth
+		eloquactl config set-credentials sandbox --password=secret
+
+		# Use OAuth2 authorization code grant
+		eloquactl config set-credentials prod --auth-provider=oauth2 --client-id=ID --client-secret=SECRET
+
+		# Use OAuth2 resource owner password grant
+		eloquactl config set-credentials prod --auth-provider=oauth2 --grant=password --password=secret
+
+		# Keep the password in the OS keyring
+		eloquactl config set-credentials prod --source=keyring --password=secret
+
+		# Keep the password in an encrypted file
+		eloquactl config set-credentials prod --source=file --path=$HOME/.eloquactl/prod.enc --password=secret
+
+		# Get the password from a credential helper
+		eloquactl config set-credentials prod --source=exec --exec-command=vault-eloqua --exec-arg=prod`)
+)
+
+type SetCredentialsOptions struct {
+	AuthProvider string
+	Password     string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Grant        string
+
+	Source          string
+	PasswordEnv     string
+	ClientSecretEnv string
+	Path            string
+	ExecCommand     string
+	ExecArgs        []string
+}
+
+func NewCmdConfigSetCredentials() *cobra.Command {
+	o := &SetCredentialsOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "set-credentials NAME",
+		Short:   "Set a credentials entry in the config file",
+		Long:    setCredentialsLong,
+		Example: setCredentialsExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return cmdutil.ValidationErrorf("set-credentials needs exactly one credentials name")
+			}
+
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(cmd, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&o.AuthProvider, "auth-provider", "", "Authentication method. One of: basic|oauth2.")
+	cmd.Flags().StringVar(&o.Password, "password", "", "Password of the user.")
+	cmd.Flags().StringVar(&o.ClientID, "client-id", "", "OAuth2 client id of the App Cloud app.")
+	cmd.Flags().StringVar(&o.ClientSecret, "client-secret", "", "OAuth2 client secret of the App Cloud app.")
+	cmd.Flags().StringVar(&o.RedirectURL, "redirect-url", "", "OAuth2 redirect URL, the local callback listener is started on it.")
+	cmd.Flags().StringVar(&o.Grant, "grant", "", "OAuth2 grant type. One of: authorization_code|password.")
+	cmd.Flags().StringVar(&o.Source, "source", "", "Where the secrets are kept. One of: inline|env|keyring|file|exec.")
+	cmd.Flags().StringVar(&o.PasswordEnv, "password-env", "", "Environment variable holding the password, used by the env source.")
+	cmd.Flags().StringVar(&o.ClientSecretEnv, "client-secret-env", "", "Environment variable holding the client secret, used by the env source.")
+	cmd.Flags().StringVar(&o.Path, "path", "", "Path of the encrypted file, used by the file source.")
+	cmd.Flags().StringVar(&o.ExecCommand, "exec-command", "", "Credential helper command, used by the exec source.")
+	cmd.Flags().StringSliceVar(&o.ExecArgs, "exec-arg", []string{}, "Arguments of the credential helper command.")
+
+	return cmd
+}
+
+func (o *SetCredentialsOptions) Validate() error {
+	switch o.AuthProvider {
+	case "", config.AuthProviderBasic, config.AuthProviderOAuth2:
+	default:
+		return fmt.Errorf("unknown auth provider %q", o.AuthProvider)
+	}
+
+	switch o.Grant {
+	case "", config.GrantAuthorizationCode, config.GrantPassword:
+	default:
+		return fmt.Errorf("unknown grant %q", o.Grant)
+	}
+
+	switch o.Source {
+	case "", config.SourceInline, config.SourceEnv, config.SourceKeyring, config.SourceExec:
+	case config.SourceFile:
+		if o.Path == "" {
+			return errors.New("--source=file requires --path")
+		}
+	default:
+		return fmt.Errorf("unknown credential source %q", o.Source)
+	}
+
+	return nil
+}
+
+func (o *SetCredentialsOptions) Run(cmd *cobra.Command, name string) error {
+	c, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	cred := config.Credential{}
+	if existing, err := c.Credential(name); err == nil {
+		cred = *existing
+	}
+
+	if cmd.Flags().Changed("auth-provider") {
+		cred.AuthProvider = o.AuthProvider
+	}
+
+	if cmd.Flags().Changed("source") {
+		cred.Source = &config.CredentialSource{
+			Type:            o.Source,
+			PasswordEnv:     o.PasswordEnv,
+			ClientSecretEnv: o.ClientSecretEnv,
+			Path:            o.Path,
+			Command:         o.ExecCommand,
+			Args:            o.ExecArgs,
+		}
+		if o.Source == config.SourceInline {
+			cred.Source = nil
+		}
+		// secrets kept elsewhere must not stay in the config file
+		if cred.Source != nil {
+			cred.Password = ""
+			if cred.OAuth2 != nil {
+				cred.OAuth2.ClientSecret = ""
+			}
+		}
+	}
+
+	if cred.AuthProvider == config.AuthProviderOAuth2 && cred.OAuth2 == nil {
+		cred.OAuth2 = &config.OAuth2{}
+	}
+	if cred.OAuth2 != nil {
+		if cmd.Flags().Changed("client-id") {
+			cred.OAuth2.ClientID = o.ClientID
+		}
+		if cmd.Flags().Changed("redirect-url") {
+			cred.OAuth2.RedirectURL = o.RedirectURL
+		}
+		if cmd.Flags().Changed("grant") {
+			cred.OAuth2.Grant = o.Grant
+		}
+	}
+
+	if cmd.Flags().Changed("password") || cmd.Flags().Changed("client-secret") {
+		secrets, err := credentials.NewSource(cred.Source, &cred)
+		if err != nil {
+			return err
+		}
+
+		// keep the secret that is not being set
+		current, err := secrets.Get(name)
+		if err != nil || current == nil {
+			current = &credentials.Secrets{}
+		}
+		if cmd.Flags().Changed("password") {
+			current.Password = o.Password
+		}
+		if cmd.Flags().Changed("client-secret") {
+			current.ClientSecret = o.ClientSecret
+		}
+
+		if err := credentials.Store(name, &cred, current); err != nil {
+			return err
+		}
+	}
+
+	c.SetCredential(name, cred)
+	if err := c.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Credentials %q set.\n", name)
+	return nil
+}
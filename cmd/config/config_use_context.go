@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/elqx/eloquactl/pkg/config"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	useContextExample = templates.Examples(`
+		# Use the context for the sandbox instance
+		eloquactl config use-context sandbox`)
+)
+
+func NewCmdConfigUseContext() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "use-context CONTEXT_NAME",
+		Aliases: []string{"use"},
+		Short:   "Set the current-context in the config file",
+		Example: useContextExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return cmdutil.ValidationErrorf("use-context needs exactly one context name")
+			}
+
+			return useContext(args[0])
+		},
+	}
+
+	return cmd
+}
+
+func useContext(name string) error {
+	c, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.Context(name); err != nil {
+		return err
+	}
+
+	c.CurrentContext = name
+	if err := c.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Switched to context %q.\n", name)
+	return nil
+}
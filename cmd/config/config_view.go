@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/elqx/eloquactl/pkg/config"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+const redacted = "REDACTED"
+
+var (
+	viewExample = templates.Examples(`
+		# Show the config file, secrets are redacted
+		eloquactl config view
+
+		# Show only the current context
+		eloquactl config view --minify`)
+)
+
+type ViewOptions struct {
+	Minify bool
+	Raw    bool
+}
+
+func NewCmdConfigView() *cobra.Command {
+	o := &ViewOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "view",
+		Short:   "Display the config file",
+		Example: viewExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.Minify, "minify", false, "Remove all information not used by the current context.")
+	cmd.Flags().BoolVar(&o.Raw, "raw", false, "Display raw secrets.")
+
+	return cmd
+}
+
+func (o *ViewOptions) Run() error {
+	c, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	if o.Minify {
+		ctx, err := c.Context("")
+		if err != nil {
+			return err
+		}
+
+		m := &config.Config{CurrentContext: c.CurrentContext}
+		m.SetContext(c.CurrentContext, *ctx)
+		if cred, err := c.Credential(ctx.Credentials); err == nil {
+			m.SetCredential(ctx.Credentials, *cred)
+		}
+		c = m
+	}
+
+	if !o.Raw {
+		for i := range c.Credentials {
+			cred := &c.Credentials[i].Credential
+			if cred.Password != "" {
+				cred.Password = redacted
+			}
+			if cred.OAuth2 != nil && cred.OAuth2.ClientSecret != "" {
+				cred.OAuth2.ClientSecret = redacted
+			}
+		}
+	}
+
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(string(data))
+	return nil
+}
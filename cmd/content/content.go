@@ -0,0 +1,43 @@
+package content
+
+import (
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+const (
+	contactImportsPath = "/contacts/imports"
+	contactExportsPath = "/contacts/exports"
+
+	// defaultContentColumn is the column of the HTML of each contact.
+	defaultContentColumn = "Content"
+)
+
+var (
+	contentLong = templates.LongDesc(`
+		Render and push the per contact HTML of App Cloud content services.
+
+		Content services inject HTML into emails and landing pages. "content render" calls
+		a content service for contacts, read from files or exported from Eloqua, and prints
+		the HTML of every contact. "content import" uploads the HTML of the contacts to an
+		execution of a content service instance, e.g. to push blocks rendered beforehand.`)
+)
+
+func NewCmdContent(f *factory.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "content SUBCOMMAND",
+		Short: "Render and push the HTML of content service instances",
+		Long:  contentLong,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdutil.ValidationErrorf("you must specify a subcommand. See 'eloquactl content -h' for help and examples")
+		},
+	}
+
+	// create subcommands
+	cmd.AddCommand(NewCmdContentRender(f))
+	cmd.AddCommand(NewCmdContentImport(f))
+
+	return cmd
+}
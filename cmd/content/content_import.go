@@ -0,0 +1,198 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	contentImportLong = templates.LongDesc(`
+		Upload the HTML of contacts to an execution of a content service instance.
+
+		The contacts are read from files, CSV with a header row, JSON arrays of objects or
+		newline delimited JSON, e.g. the output of "content render". The HTML of every
+		contact is in the --content-column. Contacts are matched by the email address
+		column, unless --identifier is given; other columns are ignored.
+
+		A contact import of the content of the execution is created, the contacts are
+		uploaded and synced, and the result of every row is printed. The exit code is 6
+		when some rows were rejected.`)
+
+	contentImportExample = templates.Examples(`
+		# Push the content rendered beforehand to an execution
+		eloquactl content import --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f --execution=1234 -f rendered.ndj
+
+		# Render and push the content in one go, matching contacts by id
+		eloquactl content render --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f --execution=1234 \
+		  --url=http://localhost:8080/offers/notify --filter="EXISTS('{{ContactSegment[42]}}')" |
+		  eloquactl content import --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f --execution=1234 -f - \
+		  --identifier=ContactID --fields='ContactID:{{Contact.Id}}'`)
+)
+
+type ContentImportOptions struct {
+	Client        func() (*bulk.BulkClient, error)
+	Cache         func() (*cache.Cache, error)
+	DryRun        *bool
+	ImportFlags   *cmdutil.ImportFlags
+	FileNameFlags *cmdutil.FileNameFlags
+	PrintFlags    *cmdutil.PrintFlags
+
+	Instance      string
+	Execution     int
+	ContentColumn string
+}
+
+func NewContentImportOptions(f *factory.Factory) *ContentImportOptions {
+	return &ContentImportOptions{
+		Client:        f.BulkClient,
+		Cache:         f.Cache,
+		DryRun:        &f.DryRun,
+		ImportFlags:   cmdutil.NewImportFlags(),
+		FileNameFlags: cmdutil.NewFileNameFlags(),
+		PrintFlags:    cmdutil.NewPrintFlags(),
+		ContentColumn: defaultContentColumn,
+	}
+}
+
+func NewCmdContentImport(f *factory.Factory) *cobra.Command {
+	o := NewContentImportOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "import --instance=INSTANCE --execution=EXECUTION -f FILENAME",
+		Short:   "Upload the HTML of contacts to a content service instance",
+		Long:    contentImportLong,
+		Example: contentImportExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run()
+		},
+	}
+
+	o.ImportFlags.AddFlags(cmd)
+	o.FileNameFlags.AddFlags(cmd)
+	o.PrintFlags.AddFlags(cmd)
+	cmd.Flags().StringVarP(&o.Instance, "instance", "i", "", "The id of the content service instance.")
+	cmd.Flags().IntVar(&o.Execution, "execution", 0, "The id of the execution the content is for.")
+	cmd.Flags().StringVar(&o.ContentColumn, "content-column", o.ContentColumn, "The column of the HTML of the contacts.")
+
+	return cmd
+}
+
+func (o *ContentImportOptions) Validate() error {
+	if err := cmdutil.ValidateInstance(o.Instance); err != nil {
+		return err
+	}
+
+	if o.Execution <= 0 {
+		return errors.New("--execution is required")
+	}
+
+	if o.ContentColumn == "" {
+		return errors.New("--content-column must not be empty")
+	}
+
+	if len(*o.FileNameFlags.FileNames) == 0 {
+		return errors.New("you must specify the contacts with -f")
+	}
+
+	if err := o.ImportFlags.Validate(); err != nil {
+		return err
+	}
+
+	return o.PrintFlags.Validate()
+}
+
+func (o *ContentImportOptions) Run() error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+
+	files, err := o.FileNameFlags.Files(".csv", ".json", ".ndj")
+	if err != nil {
+		return err
+	}
+
+	columns, records, err := cmdutil.ReadRecordFiles(files)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return cmdutil.ValidationErrorf("no contacts to import the content of")
+	}
+
+	// the content column is not a contact field
+	var contactColumns []string
+	found := false
+	for _, c := range columns {
+		if c == o.ContentColumn {
+			found = true
+			continue
+		}
+		contactColumns = append(contactColumns, c)
+	}
+	if !found {
+		return cmdutil.ValidationErrorf("there is no %s column, set the column of the HTML with --content-column", o.ContentColumn)
+	}
+
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	c, err := o.Cache()
+	if err != nil {
+		return err
+	}
+
+	md := &bulkapi.Metadata{Client: client, Cache: c}
+	column, statement, err := cmdutil.ContactIdentifier(ctx, md, contactColumns, o.ImportFlags)
+	if err != nil {
+		return err
+	}
+	if column == o.ContentColumn {
+		return cmdutil.ValidationErrorf("the identifier and the content column must differ")
+	}
+
+	name := *o.ImportFlags.Name
+	if name == "" {
+		name = fmt.Sprintf("eloquactl content %s %d", o.Instance, o.Execution)
+	}
+
+	i := &bulkapi.Import{
+		Name: name,
+		Fields: map[string]string{
+			column:          statement,
+			o.ContentColumn: bulkapi.ContentField(o.Instance, o.Execution),
+		},
+		IdentifierFieldName:              column,
+		IsUpdatingMultipleMatchedRecords: *o.ImportFlags.IsUpdatingMultipleMatchedRecords,
+		UpdateRule:                       *o.ImportFlags.UpdateRule,
+		AutoDeleteDuration:               *o.ImportFlags.StagingFlags.AutoDeleteDuration,
+		DataRetentionDuration:            *o.ImportFlags.StagingFlags.DataRetentionDuration,
+	}
+
+	if *o.DryRun {
+		return cmdutil.PrintDryRun("POST", contactImportsPath, i)
+	}
+
+	results, err := cmdutil.RunImport(ctx, client, contactImportsPath, i, records, o.ImportFlags.SyncFlags, *o.ImportFlags.KeepDefinition)
+	if err != nil {
+		return err
+	}
+
+	return cmdutil.PrintImportResults(results, o.PrintFlags, func(imported, total int) string {
+		return fmt.Sprintf("Imported the content of %d of %d contacts into execution %d of content instance %s", imported, total, o.Execution, o.Instance)
+	})
+}
@@ -0,0 +1,339 @@
+package content
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/oauth1"
+	"github.com/elqx/eloquactl/pkg/printers"
+	"github.com/elqx/eloquactl/pkg/simulator"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+const (
+	renderFields = "ContactID:{{Contact.Id}},EmailAddress:{{Contact.Field(C_EmailAddress)}}"
+)
+
+var (
+	contentRenderLong = templates.LongDesc(`
+		Render the HTML of a content service for contacts.
+
+		The contacts are read from files, CSV with a header row, JSON arrays of objects or
+		newline delimited JSON, or exported from Eloqua with the --filter and --fields of a
+		contacts export. Unless --fields is given, the contact id and email address are
+		exported, the default record definition of "eloquactl serve".
+
+		The service is called for every contact in a notify call signed as Eloqua signs
+		them, unless --insecure is given. Its response is the HTML of the contact. The
+		contacts are printed with their HTML in the --content-column, as newline delimited
+		JSON by default, which "content import" reads. Contacts the service failed for are
+		left out; the exit code is then 6.`)
+
+	contentRenderExample = templates.Examples(`
+		# Render the content of the contacts of segment 42
+		eloquactl content render --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f \
+		  --url=http://localhost:8080/offers/notify --filter="EXISTS('{{ContactSegment[42]}}')" > rendered.ndj
+
+		# Render the content of the contacts in a file with another app
+		eloquactl content render --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f -f contacts.csv \
+		  --url=https://apps.example.com/offers/notify --client-id=ID --client-secret=SECRET`)
+)
+
+type ContentRenderOptions struct {
+	Client         func() (*bulk.BulkClient, error)
+	AppCredentials func() (string, string, error)
+	DryRun         *bool
+	AppFlags       *cmdutil.AppFlags
+	FileNameFlags  *cmdutil.FileNameFlags
+	SyncFlags      *cmdutil.SyncFlags
+	PrintFlags     *cmdutil.PrintFlags
+
+	URL            string
+	Instance       string
+	Execution      int
+	Filter         string
+	Fields         string
+	MaxRecords     uint
+	ContentColumn  string
+	Parallel       int
+	KeepDefinition bool
+	Insecure       bool
+}
+
+func NewContentRenderOptions(f *factory.Factory) *ContentRenderOptions {
+	o := &ContentRenderOptions{
+		Client:         f.BulkClient,
+		AppCredentials: f.AppCredentials,
+		DryRun:         &f.DryRun,
+		AppFlags:       cmdutil.NewAppFlags(),
+		FileNameFlags:  cmdutil.NewFileNameFlags(),
+		SyncFlags:      cmdutil.NewSyncFlags(),
+		PrintFlags:     cmdutil.NewPrintFlags(),
+		Fields:         renderFields,
+		ContentColumn:  defaultContentColumn,
+		Parallel:       4,
+	}
+	*o.PrintFlags.OutputFormat = "ndj"
+	return o
+}
+
+func NewCmdContentRender(f *factory.Factory) *cobra.Command {
+	o := NewContentRenderOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "render --instance=INSTANCE --url=URL (-f FILENAME | --filter=FILTER)",
+		Short:   "Render the HTML of a content service for contacts",
+		Long:    contentRenderLong,
+		Example: contentRenderExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run()
+		},
+	}
+
+	o.AppFlags.AddFlags(cmd)
+	o.FileNameFlags.AddFlags(cmd)
+	o.SyncFlags.AddFlags(cmd)
+	o.PrintFlags.AddFlags(cmd)
+	cmd.Flags().StringVar(&o.URL, "url", "", "The notification URL of the content service.")
+	cmd.Flags().StringVarP(&o.Instance, "instance", "i", "", "The id of the content service instance.")
+	cmd.Flags().IntVar(&o.Execution, "execution", 0, "The id of the execution. Defaults to a random one.")
+	cmd.Flags().StringVar(&o.Filter, "filter", "", "The filter of the contacts to export, e.g. \"EXISTS('{{ContactSegment[42]}}')\".")
+	cmd.Flags().StringVar(&o.Fields, "fields", o.Fields, "The fields of the contacts to export, e.g. Email:{{Contact.Field(C_EmailAddress)}}.")
+	cmd.Flags().UintVar(&o.MaxRecords, "max-records", 0, "The maximum number of contacts to export. Zero means all.")
+	cmd.Flags().StringVar(&o.ContentColumn, "content-column", o.ContentColumn, "The column of the HTML of the contacts.")
+	cmd.Flags().IntVar(&o.Parallel, "parallel", o.Parallel, "The number of calls to the service made at the same time.")
+	cmd.Flags().BoolVar(&o.KeepDefinition, "keep-definition", false, "If true, the export definition is not deleted when the command is interrupted.")
+	cmd.Flags().BoolVar(&o.Insecure, "insecure", false, "Don't sign the calls.")
+
+	return cmd
+}
+
+func (o *ContentRenderOptions) Validate() error {
+	if o.URL == "" {
+		return errors.New("--url is required")
+	}
+
+	if err := cmdutil.ValidateInstance(o.Instance); err != nil {
+		return err
+	}
+
+	if o.Execution < 0 {
+		return errors.New("--execution must not be negative")
+	}
+
+	if (len(*o.FileNameFlags.FileNames) == 0) == (o.Filter == "") {
+		return errors.New("you must specify the contacts with either -f or --filter")
+	}
+
+	if _, err := cmdutil.ParseFields(o.Fields); err != nil {
+		return err
+	}
+
+	if o.ContentColumn == "" {
+		return errors.New("--content-column must not be empty")
+	}
+
+	if o.Parallel < 1 {
+		return errors.New("--parallel must be at least 1")
+	}
+
+	if o.Insecure && *o.AppFlags.ClientID != "" {
+		return errors.New("--insecure and --client-id are mutually exclusive")
+	}
+
+	if err := o.AppFlags.Validate(); err != nil {
+		return err
+	}
+
+	if err := o.SyncFlags.Validate(); err != nil {
+		return err
+	}
+
+	return o.PrintFlags.Validate()
+}
+
+func (o *ContentRenderOptions) Run() error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+
+	if *o.DryRun && o.Filter != "" {
+		e, err := o.newExport()
+		if err != nil {
+			return err
+		}
+		return cmdutil.PrintDryRun("POST", contactExportsPath, e)
+	}
+
+	records, err := o.contacts(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		fmt.Fprintln(os.Stderr, "No contacts to render")
+		return nil
+	}
+
+	if o.Execution == 0 {
+		o.Execution = rand.New(rand.NewSource(time.Now().UnixNano())).Intn(1000000) + 1
+	}
+
+	s := &simulator.Simulator{URL: o.URL, Instance: o.Instance, Execution: o.Execution}
+	if !o.Insecure {
+		id, secret, err := o.AppFlags.Credentials(o.AppCredentials)
+		if err != nil {
+			return err
+		}
+		s.Signer = oauth1.NewSigner(id, secret)
+	}
+
+	if _, err := s.NotifyURL(); err != nil {
+		return &cmdutil.ValidationError{Err: err}
+	}
+
+	rendered, failed, err := o.render(ctx, s, records)
+	if err != nil {
+		return err
+	}
+
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+
+	if len(rendered) > 0 {
+		w := printers.NewTabWriter(os.Stdout)
+		if err := printer.PrintResource(rendered, w); err != nil {
+			return err
+		}
+		w.Flush()
+	}
+
+	fmt.Fprintf(os.Stderr, "Rendered the content of %d of %d contacts in execution %d\n", len(rendered), len(records), o.Execution)
+	switch {
+	case ctx.Err() != nil:
+		return &cmdutil.InterruptedError{}
+	case len(rendered) == 0:
+		return errors.New("the service failed for all contacts")
+	case failed > 0:
+		return &cmdutil.PartialError{Err: fmt.Errorf("the service failed for %d of %d contacts", failed, len(records))}
+	}
+	return nil
+}
+
+// contacts returns the contacts of the files or the export.
+func (o *ContentRenderOptions) contacts(ctx context.Context) ([]map[string]string, error) {
+	if len(*o.FileNameFlags.FileNames) > 0 {
+		files, err := o.FileNameFlags.Files(".csv", ".json", ".ndj")
+		if err != nil {
+			return nil, err
+		}
+
+		_, records, err := cmdutil.ReadRecordFiles(files)
+		return records, err
+	}
+
+	e, err := o.newExport()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := o.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := cmdutil.ExportContacts(ctx, client, e, o.SyncFlags, o.KeepDefinition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export contacts: %w", err)
+	}
+	return records, nil
+}
+
+func (o *ContentRenderOptions) newExport() (*bulk.Export, error) {
+	fields, err := cmdutil.ParseFields(o.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bulk.Export{
+		Name:       "eloquactl content render " + o.Instance,
+		Fields:     fields,
+		Filter:     o.Filter,
+		MaxRecords: o.MaxRecords,
+	}, nil
+}
+
+// render calls the service for every contact, --parallel at a time. It
+// returns the contacts rendered, in order, with their HTML, and the number
+// of contacts the service failed for.
+func (o *ContentRenderOptions) render(ctx context.Context, s *simulator.Simulator, records []map[string]string) ([]bulk.Item, int, error) {
+	batches := simulator.Batches(records, 1)
+	results := make([]*simulator.Result, len(batches))
+	errs := make([]error, len(batches))
+
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for n := 0; n < o.Parallel; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i], errs[i] = s.Notify(ctx, &batches[i])
+			}
+		}()
+	}
+
+	for i := range batches {
+		if ctx.Err() != nil {
+			break
+		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var rendered []bulk.Item
+	failed := 0
+	for i, r := range results {
+		if errs[i] != nil {
+			return nil, 0, errs[i]
+		}
+		if r == nil {
+			continue
+		}
+
+		if r.Failed() {
+			failed++
+			reason := r.Error
+			if reason == "" {
+				reason = fmt.Sprintf("status %d", r.StatusCode)
+			}
+			fmt.Fprintf(os.Stderr, "Contact %d: the service failed: %s\n", i+1, reason)
+			continue
+		}
+
+		item := bulk.Item{}
+		for k, v := range records[i] {
+			item[k] = v
+		}
+		item[o.ContentColumn] = strings.TrimRight(r.Body, "\r\n")
+		rendered = append(rendered, item)
+	}
+	return rendered, failed, nil
+}
@@ -1,24 +1,264 @@
 package decide
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/printers"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
 	"github.com/spf13/cobra"
 )
 
-func NewCmdDecide() *cobra.Command {
-	// cmd represents the decide command
+const (
+	contactImportsPath = "/contacts/imports"
+)
+
+var (
+	decideLong = templates.LongDesc(`
+		Send contacts waiting in a decision service instance down its yes or no path.
+
+		The contacts of each path are read from files, CSV with a header row, JSON arrays
+		of objects or newline delimited JSON, or exported from segments. Contacts are
+		matched by the email address column, unless --identifier is given; other columns
+		are ignored.
+
+		A contact import with the setStatus sync action is created for each path, the
+		contacts are uploaded and synced, and the number of contacts moved down each path
+		is printed. The exit code is 6 when some contacts were rejected.`)
+
+	decideExample = templates.Examples(`
+		# Decide for the contacts in two files
+		eloquactl decide --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f --yes=qualified.csv --no=rejected.csv
+
+		# Send the contacts of segment 42 down the yes path of a single execution
+		eloquactl decide --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f --execution=1234 --yes-segment=42
+
+		# Print the import definitions without creating them
+		eloquactl decide --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f --yes=qualified.csv --dry-run`)
+)
+
+// path is a path of a decision and the contacts to send down it.
+type path struct {
+	status   string
+	files    []string
+	segments []int
+}
+
+// pathResult is a line of the summary.
+type pathResult struct {
+	status   string
+	contacts int
+	rejects  []bulkapi.ImportResult
+}
+
+type DecideOptions struct {
+	Client      func() (*bulk.BulkClient, error)
+	Cache       func() (*cache.Cache, error)
+	DryRun      *bool
+	ImportFlags *cmdutil.ImportFlags
+
+	Instance    string
+	Execution   int
+	Yes         []string
+	No          []string
+	YesSegments []int
+	NoSegments  []int
+}
+
+func NewDecideOptions(f *factory.Factory) *DecideOptions {
+	return &DecideOptions{
+		Client:      f.BulkClient,
+		Cache:       f.Cache,
+		DryRun:      &f.DryRun,
+		ImportFlags: cmdutil.NewImportFlags(),
+	}
+}
+
+func NewCmdDecide(f *factory.Factory) *cobra.Command {
+	o := NewDecideOptions(f)
+
 	cmd := &cobra.Command{
-		Use:     "decide --decision=...",
-		Short:   "import contacts to the decision service",
-		Long:    `long description`,
-		Example: "examples",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("decide called")
+		Use:     "decide --instance=INSTANCE [--yes=FILENAME] [--no=FILENAME]",
+		Short:   "Send contacts down the yes or no path of a decision service instance",
+		Long:    decideLong,
+		Example: decideExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run()
 		},
 	}
 
-	cmd.Flags().StringP("instance", "i", "", "Decision service instance id")
+	o.ImportFlags.AddFlags(cmd)
+	cmd.Flags().StringVarP(&o.Instance, "instance", "i", "", "Decision service instance id")
+	cmd.Flags().IntVar(&o.Execution, "execution", 0, "The id of the execution the contacts are waiting in. Defaults to any execution of the instance.")
+	cmd.Flags().StringSliceVar(&o.Yes, "yes", nil, "Files with the contacts to send down the yes path, - for stdin.")
+	cmd.Flags().StringSliceVar(&o.No, "no", nil, "Files with the contacts to send down the no path, - for stdin.")
+	cmd.Flags().IntSliceVar(&o.YesSegments, "yes-segment", nil, "Ids of the segments with the contacts to send down the yes path.")
+	cmd.Flags().IntSliceVar(&o.NoSegments, "no-segment", nil, "Ids of the segments with the contacts to send down the no path.")
 
 	return cmd
 }
+
+func (o *DecideOptions) Validate() error {
+	if err := cmdutil.ValidateInstance(o.Instance); err != nil {
+		return err
+	}
+
+	if o.Execution < 0 {
+		return errors.New("--execution must not be negative")
+	}
+
+	if len(o.Yes)+len(o.No)+len(o.YesSegments)+len(o.NoSegments) == 0 {
+		return errors.New("you must specify the contacts with --yes, --no, --yes-segment or --no-segment")
+	}
+
+	stdin := 0
+	for _, file := range append(append([]string{}, o.Yes...), o.No...) {
+		if file == "-" {
+			stdin++
+		}
+	}
+	if stdin > 1 {
+		return errors.New("only one path can be read from stdin")
+	}
+
+	return o.ImportFlags.Validate()
+}
+
+func (o *DecideOptions) Run() error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+
+	paths := []path{
+		{status: bulkapi.StatusYes, files: o.Yes, segments: o.YesSegments},
+		{status: bulkapi.StatusNo, files: o.No, segments: o.NoSegments},
+	}
+
+	// read all files before importing anything
+	columns := map[string][]string{}
+	records := map[string][]map[string]string{}
+	for _, p := range paths {
+		c, r, err := cmdutil.ReadRecordFiles(p.files)
+		if err != nil {
+			return err
+		}
+		columns[p.status], records[p.status] = c, r
+	}
+
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	c, err := o.Cache()
+	if err != nil {
+		return err
+	}
+	md := &bulkapi.Metadata{Client: client, Cache: c}
+
+	var results []pathResult
+	// all the rows imported, for the exit code
+	var imported []bulkapi.ImportResult
+	for _, p := range paths {
+		if len(p.files)+len(p.segments) == 0 {
+			continue
+		}
+
+		column, statement, err := cmdutil.ContactIdentifier(ctx, md, columns[p.status], o.ImportFlags)
+		if err != nil {
+			return err
+		}
+
+		i := o.newImport(p.status, column, statement)
+		if *o.DryRun {
+			if err := cmdutil.PrintDryRun("POST", contactImportsPath, i); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, segment := range p.segments {
+			r, err := cmdutil.SegmentContacts(ctx, client, segment, column, statement, o.ImportFlags.SyncFlags, *o.ImportFlags.KeepDefinition)
+			if err != nil {
+				return err
+			}
+			records[p.status] = append(records[p.status], r...)
+		}
+
+		if len(records[p.status]) == 0 {
+			fmt.Fprintf(os.Stderr, "No contacts to send down the %s path\n", p.status)
+			results = append(results, pathResult{status: p.status})
+			continue
+		}
+
+		r, err := cmdutil.RunImport(ctx, client, contactImportsPath, i, records[p.status], o.ImportFlags.SyncFlags, *o.ImportFlags.KeepDefinition)
+		if err != nil {
+			return err
+		}
+
+		results = append(results, pathResult{status: p.status, contacts: len(r), rejects: cmdutil.Rejects(r)})
+		imported = append(imported, r...)
+	}
+
+	if *o.DryRun {
+		return nil
+	}
+
+	printSummary(results)
+
+	for _, r := range results {
+		if len(r.rejects) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(os.Stderr, "\nRejected from the %s path:\n", r.status)
+		w := printers.NewTabWriter(os.Stderr)
+		(&printers.TablePrinter{}).PrintResource(r.rejects, w)
+		w.Flush()
+	}
+
+	return cmdutil.ImportError(imported)
+}
+
+// newImport returns the import definition setting the status of the
+// contacts in the instance.
+func (o *DecideOptions) newImport(status, column, statement string) *bulkapi.Import {
+	name := *o.ImportFlags.Name
+	if name == "" {
+		name = fmt.Sprintf("eloquactl decide %s %s", o.Instance, status)
+	}
+
+	return &bulkapi.Import{
+		Name:                             name,
+		Fields:                           map[string]string{column: statement},
+		IdentifierFieldName:              column,
+		IsUpdatingMultipleMatchedRecords: *o.ImportFlags.IsUpdatingMultipleMatchedRecords,
+		UpdateRule:                       *o.ImportFlags.UpdateRule,
+		AutoDeleteDuration:               *o.ImportFlags.StagingFlags.AutoDeleteDuration,
+		DataRetentionDuration:            *o.ImportFlags.StagingFlags.DataRetentionDuration,
+		SyncActions: []bulkapi.SyncAction{{
+			Destination: bulkapi.DecisionDestination(o.Instance, o.Execution),
+			Action:      bulkapi.SyncActionSetStatus,
+			Status:      status,
+		}},
+	}
+}
+
+// printSummary prints the number of contacts moved down each path.
+func printSummary(results []pathResult) {
+	w := printers.NewTabWriter(os.Stdout)
+	fmt.Fprintln(w, "PATH\tCONTACTS\tMOVED\tREJECTED")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", r.status, r.contacts, r.contacts-len(r.rejects), len(r.rejects))
+	}
+	w.Flush()
+}
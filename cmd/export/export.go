@@ -23,29 +23,27 @@ package export
 
 import (
 	"context"
-	"fmt"
-	//	"io"
+	"crypto/sha256"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"regexp"
-	"strconv"
+	"sort"
 	"strings"
-	"time"
 
 	"github.com/elqx/eloqua-go/eloqua/bulk"
-	"github.com/elqx/eloqua-go/eloqua/pkg/auth"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/fieldsets"
 	"github.com/elqx/eloquactl/pkg/printers"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
-	//	cmdutil "github.com/elqx/eloquactl/pkg/util"
-)
-
-const (
-	batchSize  = 25000
-	apiVersion = "2.0"
 )
 
-var (
 /*
 	// Accounts
 	exportAccountsShort = "Export Eloqua accounts to a file ot stdout."
@@ -103,21 +101,56 @@ var (
 		# Export specific contact fields
 		eloquactl export contacts --email-addresses=test1@test.com --fields=FirstName:{{Contact.Field(C_FirstName)}},LastName:{{Contact.Fields(C_LastName)}}`)
 */
+
+var (
+	exportLong = templates.LongDesc(`
+		Export a resource from Eloqua using subcommands like "eloquactl export contacts".
+
+		Exports can also be described in YAML or JSON job files and run with -f,
+		e.g. the nightly extracts kept in version control. A summary of the jobs
+		is printed to stderr.`)
+
+	exportExample = templates.Examples(`
+		# Run all export jobs in a directory, four at a time
+		eloquactl export -f jobs/ --parallel=4
+
+		# Print the definitions of the jobs without running them
+		eloquactl export -f jobs/contacts.yaml --dry-run`)
 )
 
 type Fields map[string]string
 
-func NewCmdExport() *cobra.Command {
+// ExportOptions runs the export jobs given by -f.
+type ExportOptions struct {
+	Factory       *factory.Factory
+	FileNameFlags *cmdutil.FileNameFlags
+
+	Parallel int
+}
+
+func NewCmdExport(f *factory.Factory) *cobra.Command {
+	o := &ExportOptions{
+		Factory:       f,
+		FileNameFlags: cmdutil.NewFileNameFlags(),
+	}
+
 	cmd := &cobra.Command{
 		Use:     "export",
 		Short:   "export a resource from Eloqua",
-		Long:    ``,
-		Example: "examples",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("You must specify the type of resource to export. See 'eloquactl export -h' for help and examples.")
+		Long:    exportLong,
+		Example: exportExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(*o.FileNameFlags.FileNames) == 0 || len(args) > 0 {
+				return cmdutil.ValidationErrorf("you must specify the type of resource to export or export job files. See 'eloquactl export -h' for help and examples")
+			}
+
+			return o.Run()
 		},
 	}
 
+	o.FileNameFlags.AddFlags(cmd)
+	cmd.Flags().IntVar(&o.Parallel, "parallel", 1, "Number of export jobs given by -f to run at once.")
+
 	/*
 		cmdExportAccounts := CmdBuilder(cmd, RunExportAccounts, "accounts", exportAccountsShort,
 			exportAccountsLong, exportAccountsExample, aliasOpt("account"))
@@ -135,77 +168,152 @@ func NewCmdExport() *cobra.Command {
 	*/
 
 	// create subcommands
-	cmd.AddCommand(NewCmdExportActivities())
-	cmd.AddCommand(NewCmdExportAccounts())
-	cmd.AddCommand(NewCmdExportCdos())
-	cmd.AddCommand(NewCmdExportContacts())
-	cmd.AddCommand(NewCmdExportScores())
+	cmd.AddCommand(NewCmdExportActivities(f))
+	cmd.AddCommand(NewCmdExportAccounts(f))
+	cmd.AddCommand(NewCmdExportCdos(f))
+	cmd.AddCommand(NewCmdExportContacts(f))
+	cmd.AddCommand(NewCmdExportScores(f))
+	cmd.AddCommand(NewCmdExportDefinitions(f))
 
 	return cmd
 }
 
-// export data given export definition
-func export(ctx context.Context, ex *bulk.Export, keys *[]string, printer *printers.ResourcePrinter, client *bulk.BulkClient) {
-	// create sync definition
-	sync, err := client.Syncs.Create(ctx, &bulk.Sync{SyncedInstanceURI: ex.Uri})
+// Run runs the export jobs.
+func (o *ExportOptions) Run() error {
+	if o.Parallel < 1 {
+		return cmdutil.ValidationErrorf("--parallel must be positive")
+	}
+
+	files, err := o.FileNameFlags.Files(".yaml", ".yml", ".json")
 	if err != nil {
-		fmt.Println(err)
+		return &cmdutil.ValidationError{Err: err}
 	}
 
-	// check sync status and download
-	if err := waitSyncAndDownload(ctx, sync, keys, printer, client); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	jobs, err := loadJobs(files)
+	if err != nil {
+		return &cmdutil.ValidationError{Err: err}
 	}
 
+	if err := validateJobs(jobs, o.Parallel); err != nil {
+		return &cmdutil.ValidationError{Err: err}
+	}
+
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	return runJobs(ctx, o.Factory, jobs, o.Parallel)
 }
 
-func waitSyncAndDownload(ctx context.Context, sync *bulk.Sync, keys *[]string, printer *printers.ResourcePrinter, client *bulk.BulkClient) error {
-	syncId, err := strconv.Atoi(sync.Uri[7:])
-	if err != nil {
-		return err
+// createExport creates the export definition under path, e.g. /contacts/exports.
+// With --reuse an existing definition with the same name is returned instead.
+func createExport(ctx context.Context, client *bulk.BulkClient, path string, e *bulk.Export, ef *cmdutil.ExportFlags, create func(context.Context, *bulk.Export) (*bulk.Export, error)) (*bulk.Export, error) {
+	if e.Name == "" {
+		e.Name = defaultName(path, e)
 	}
 
-	for sync.Status != "success" && sync.Status != "error" {
-		time.Sleep(5 * time.Second)
-		sync, err = client.Syncs.Get(ctx, syncId)
+	if *ef.Reuse {
+		ex, err := bulkapi.FindExport(ctx, client, path, e.Name)
 		if err != nil {
-			return errors.New("Failed to check sync status")
+			return nil, err
 		}
-	}
 
-	if sync.Status == "error" {
-		return errors.New("Failed to sync")
+		if ex != nil {
+			fmt.Fprintf(os.Stderr, "Reusing export definition %s (%s)\n", ex.Uri, ex.Name)
+			// the definition is not ours to delete
+			*ef.KeepDefinition = true
+			return ex, nil
+		}
 	}
 
-	if err := download(ctx, syncId, keys, printer, client); err != nil {
-		return err
+	return create(ctx, e)
+}
+
+// dryRun prints the export definition that would be created under path.
+func dryRun(path string, e *bulk.Export) error {
+	if e.Name == "" {
+		e.Name = defaultName(path, e)
 	}
+	return cmdutil.PrintDryRun("POST", path, e)
+}
 
-	return nil
+// defaultName returns a name that is the same for exports of the same entity
+// with the same fields, filter and limits, so that --reuse finds the definition.
+func defaultName(path string, e *bulk.Export) string {
+	entity := strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/exports")
+
+	// maps are marshalled with sorted keys, the result is stable
+	data, _ := json.Marshal(struct {
+		Entity     string
+		Fields     map[string]string
+		Filter     string
+		MaxRecords uint
+		UTC        bool
+	}{entity, e.Fields, e.Filter, e.MaxRecords, e.AreSystemTimestampsInUTC})
+
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("eloquactl %s %x", entity, sum[:6])
 }
 
-func download(ctx context.Context, syncId int, keys *[]string, printer *printers.ResourcePrinter, client *bulk.BulkClient) error {
-	opt := &bulk.QueryOptions{Limit: batchSize, Offset: 0}
-	w := printers.NewTabWriter(os.Stdout)
+// output is where an export writes its rows.
+type output struct {
+	w    io.Writer
+	rows int
+}
 
-	for {
-		data, err := client.Syncs.GetData(ctx, syncId, opt)
-		if err != nil {
+// export data given export definition
+func export(ctx context.Context, ex *bulk.Export, keys *[]string, printer *printers.ResourcePrinter, client *bulk.BulkClient, ef *cmdutil.ExportFlags, out *output) error {
+	w := printers.NewTabWriter(out.w)
+	rows, err := cmdutil.RunExport(ctx, client, ex.Uri, ef.SyncFlags, *ef.KeepDefinition, func(items []bulk.Item) error {
+		if err := (*printer).PrintResource(items, w); err != nil {
 			return err
 		}
+		return w.Flush()
+	})
+	out.rows += rows
+	return err
+}
 
-		(*printer).PrintResource(data.Items, w)
-		w.Flush()
+// metadata returns the metadata of the instance, cached unless the cache is disabled.
+func metadata(client *bulk.BulkClient, c func() (*cache.Cache, error)) (*bulkapi.Metadata, error) {
+	mc, err := c()
+	if err != nil {
+		return nil, err
+	}
+	return &bulkapi.Metadata{Client: client, Cache: mc}, nil
+}
 
-		if !data.HasMore {
-			break
+// exportFields returns the fields of the --field-set with the --fields or the
+// fields of the export job added, empty if none is given, and the names of the
+// --fields in order, of the job fields sorted.
+func exportFields(entity string, ef *cmdutil.ExportFlags) (Fields, []string, error) {
+	fields := Fields{}
+	if *ef.FieldSet != "" {
+		set, err := fieldsets.Get(entity, *ef.FieldSet)
+		if err != nil {
+			return nil, nil, &cmdutil.ValidationError{Err: err}
 		}
 
-		opt.Offset += batchSize
+		for name, statement := range set.Fields {
+			fields[name] = statement
+		}
 	}
 
-	return nil
+	var keys []string
+	if *ef.Fields != "" {
+		k, err := parseFieldsStr(*ef.Fields, &fields)
+		if err != nil {
+			return nil, nil, &cmdutil.ValidationError{Err: err}
+		}
+		keys = k
+	}
+
+	var names []string
+	for name, statement := range ef.JobFields {
+		fields[name] = statement
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return fields, append(keys, names...), nil
 }
 
 // parseFieldsStr parses fields string into a map of a field aliases and EML field representaions
@@ -233,10 +341,15 @@ func parseFieldsStr(str string, m *Fields) ([]string, error) {
 	return k, nil
 }
 
+// checkDate checks the date flag value, empty values are not set flags.
 func checkDate(s string) error {
+	if s == "" {
+		return nil
+	}
+
 	re := regexp.MustCompile(DATE_REGEX)
 	if match := re.MatchString(s); !match {
-		return errors.New("invalid date string")
+		return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", s)
 	}
 	return nil
 }
@@ -245,20 +358,3 @@ func checkISO8601(s string) error {
 	// implementation missing
 	return nil
 }
-
-func generateName() string {
-	// implementation missing
-	return "generated name"
-}
-
-func initClient() *bulk.BulkClient {
-	bauth := viper.GetStringMap("auth")
-	bulkURL := strings.Replace(viper.GetString("bulkUrl"), "{version}", apiVersion, 1)
-	username := fmt.Sprintf("%v\\%v", bauth["company"], bauth["username"])
-	password := bauth["password"]
-
-	tr := auth.BasicAuthTransport{Username: username, Password: password.(string)}
-	client := bulk.NewClient(bulkURL, tr.Client())
-
-	return client
-}
@@ -2,13 +2,14 @@ package export
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/factory"
 	cmdutil "github.com/elqx/eloquactl/pkg/util"
 	"github.com/elqx/eloquactl/pkg/util/templates"
 	"github.com/spf13/cobra"
@@ -41,171 +42,10 @@ var (
 		eloquactl export activities --type=EmailSend --since=2019-01-01 --until=2019-02-01
 
 		# Export specific fields of EmailOpen activities
-		eloquactl export activities --type=EmailOpen --since=2019-01-01 --fields='ActivityDate:{{Activity.CreatedAt}},EmailAddress:{{Activity.Field(EmailAddress)}}'`)
-
-/*
-	activityFields = map[string]Fields{
-		"es": Fields{
-			"ActivityId": "{{Activity.Id}}",
-			"ActivityType": "{{Activity.Type}}",
-			"EmailAddress": "{{Activity.Field(EmailAddress)}}",
-			"ContactId": "{{Activity.Contact.Id}}",
-			"EmailRecipientId": "{{Activity.Field(EmailRecipientId)}}",
-			"AssetType": "{{Activity.Asset.Type}}",
-			"AssetId": "{{Activity.Asset.Id}}",
-			"AssetName": "{{Activity.Asset.Name}}",
-			"SubjectLine": "{{Activity.Field(SubjectLine)}}",
-			"EmailWebLink": "{{Activity.Field(EmailWebLink)}}",
-			"CampaignId": "{{Activity.Campaign.Id}}",
-			"ExternalId": "{{Activity.ExternalId}}",
-			"DeploymentId": "{{Activity.Field(EmailDeploymentId)}}",
-			"EmailSendType": "{{Activity.Field(EmailSendType)}}",
-			"CampaignResponseDate": "{{Activity.CampaignResponse.CreatedAt}}",
-			"CampaignResponseMemberStatus": "{{Activity.CampaignResponse.Field(MemberStatus)}}",
-		},
-		"eo": Fields{
-			"ActivityId": "{{Activity.Id}}",
-			"ActivityType": "{{Activity.Type}}",
-			"ActivityDate": "{{Activity.CreatedAt}}",
-			"EmailAddress": "{{Activity.Field(EmailAddress)}}",
-			"ContactId": "{{Activity.Contact.Id}}",
-			"IpAddress": "{{Activity.Field(IpAddress)}}",
-			"VisitorId": "{{Activity.Visitor.Id}}",
-			"EmailRecipientId": "{{Activity.Field(EmailRecipientId)}}",
-			"AssetType": "{{Activity.Asset.Type}}",
-			"AssetName": "{{Activity.Asset.Name}}",
-			"AssetId": "{{Activity.Asset.Id}}",
-			"SubjectLine": "{{Activity.Field(SubjectLine)}}",
-			"EmailWebLink": "{{Activity.Field(EmailWebLink)}}",
-			"VisitorExternalId": "{{Activity.Visitor.ExternalId}}",
-			"CampaignId": "{{Activity.Campaign.Id}}",
-			"ExternalId": "{{Activity.ExternalId}}",
-			"DeploymentId": "{{Activity.Field(EmailDeploymentId)}}",
-			"EmailSendType": "{{Activity.Field(EmailSendType)}}",
-			"CampaignResponseDate": "{{Activity.CampaignResponse.CreatedAt}}",
-			"CampaignResponseMemberStatus": "{{Activity.CampaignResponse.Field(MemberStatus)}}",
-		},
-		"ec": Fields{
-			"ActivityId": "{{Activity.Id}}",
-			"ActivityType": "{{Activity.Type}}",
-			"ActivityDate": "{{Activity.CreatedAt}}",
-			"EmailAddress": "{{Activity.Field(EmailAddress)}}",
-			"ContactId": "{{Activity.Contact.Id}}",
-			"IpAddress": "{{Activity.Field(IpAddress)}}",
-			"VisitorId": "{{Activity.Visitor.Id}}",
-			"EmailRecipientId": "{{Activity.Field(EmailRecipientId)}}",
-			"AssetType": "{{Activity.Asset.Type}}",
-			"AssetName": "{{Activity.Asset.Name}}",
-			"AssetId": "{{Activity.Asset.Id}}",
-			"SubjectLine": "{{Activity.Field(SubjectLine)}}",
-			"EmailWebLink": "{{Activity.Field(EmailWebLink)}}",
-			"EmailClickedThruLink": "{{Activity.Field(EmailClickedThruLink)}}",
-			"VisitorExternalId": "{{Activity.Visitor.ExternalId}}",
-			"CampaignId": "{{Activity.Campaign.Id}}",
-			"ExternalId": "{{Activity.ExternalId}}",
-			"DeploymentId": "{{Activity.Field(EmailDeploymentId)}}",
-			"EmailSendType": "{{Activity.Field(EmailSendType)}}",
-			"CampaignResponseDate": "{{Activity.CampaignResponse.CreatedAt}}",
-			"CampaignResponseMemberStatus": "{{Activity.CampaignResponse.Field(MemberStatus)}}",
-		},
-		"bb": Fields{
-			"ActivityId": "{{Activity.Id}}",
-			"ActivityType": "{{Activity.Type}}",
-			"ActivityDate": "{{Activity.CreatedAt}}",
-			"EmailAddress": "{{Activity.Field(EmailAddress)}}",
-			"ContactId": "{{Activity.Contact.Id}}",
-			"AssetType": "{{Activity.Asset.Type}}",
-			"AssetName": "{{Activity.Asset.Name}}",
-			"AssetId": "{{Activity.Asset.Id}}",
-			"CampaignId": "{{Activity.Campaign.Id}}",
-			"ExternalId": "{{Activity.ExternalId}}",
-			"EmailRecipientId": "{{Activity.Field(EmailRecipientId)}}",
-			"DeploymentId": "{{Activity.Field(EmailDeploymentId)}}",
-			"SmtpErrorCode": "{{Activity.Field(SmtpErrorCode)}}",
-			"SmtpStatusCode": "{{Activity.Field(SmtpStatusCode)}}",
-			"SmtpMessage": "{{Activity.Field(SmtpMessage)}}",
-
-		},
-		"fs": Fields{
-			"ActivityId": "{{Activity.Id}}",
-			"ActivityType": "{{Activity.Type}}",
-			"ActivityDate": "{{Activity.CreatedAt}}",
-			"ContactId": "{{Activity.Contact.Id}}",
-			"VisitorId": "{{Activity.Visitor.Id}}",
-			"VisitorExternalId": "{{Activity.Visitor.ExternalId}}",
-			"AssetType": "{{Activity.Asset.Type}}",
-			"AssetId": "{{Activity.Asset.Id}}",
-			"AssetName": "{{Activity.Asset.Name}}",
-			"RawData": "{{Activity.Field(RawData)}}",
-			"CampaignId": "{{Activity.Campaign.Id}}",
-			"ExternalId": "{{Activity.ExternalId}}",
-			"CampaignResponseDate": "{{Activity.CampaignResponse.CreatedAt}}",
-			"CampaignResponseMemberStatus": "{{Activity.CampaignResponse.Field(MemberStatus)}}",
-		},
-		"su": Fields{
-			"ActivityId": "{{Activity.Id}}",
-			"ActivityType": "{{Activity.Type}}",
-			"AssetId": "{{Activity.Asset.Id}}",
-			"ActivityDate": "{{Activity.CreatedAt}}",
-			"EmailAddress": "{{Activity.Field(EmailAddress)}}",
-			"ContactId": "{{Activity.Contact.Id}}",
-			"EmailRecipientId": "{{Activity.Field(EmailRecipientId)}}",
-			"AssetType": "{{Activity.Asset.Type}}",
-			"AssetName": "{{Activity.Asset.Name}}",
-			"CampaignId": "{{Activity.Campaign.Id}}",
-			"ExternalId": "{{Activity.ExternalId}}",
-		},
-		"un": Fields{
-			"ActivityId": "{{Activity.Id}}",
-			"ActivityType": "{{Activity.Type}}",
-			"AssetId": "{{Activity.Asset.Id}}",
-			"ActivityDate": "{{Activity.CreatedAt}}",
-			"EmailAddress": "{{Activity.Field(EmailAddress)}}",
-			"ContactId": "{{Activity.Contact.Id}}",
-			"EmailRecipientId": "{{Activity.Field(EmailRecipientId)}}",
-			"AssetType": "{{Activity.Asset.Type}}",
-			"AssetName": "{{Activity.Asset.Name}}",
-			"CampaignId": "{{Activity.Campaign.Id}}",
-			"ExternalId": "{{Activity.ExternalId}}",
-		},
-		"wv": Fields{
-			"ActivityId": "{{Activity.Id}}",
-			"ActivityType": "{{Activity.Type}}",
-			"ActivityDate": "{{Activity.CreatedAt}}",
-			"ContactId": "{{Activity.Contact.Id}}",
-			"VisitorId": "{{Activity.Visitor.Id}}",
-			"VisitorExternalId": "{{Activity.Visitor.ExternalId}}",
-			"ReferrerUrl": "{{Activity.Field(ReferrerUrl)}}",
-			"IpAddress": "{{Activity.Field(IpAddress)}}",
-			"NumberOfPages": "{{Activity.Field(NumberOfPages)}}",
-			"FirstPageViewUrl": "{{Activity.Field(FirstPageViewUrl)}}",
-			"Duration": "{{Activity.Field(Duration)}}",
-			"ExternalId": "{{Activity.ExternalId}}",
-			"LinkedToContactDate": "{{Activity.Field(LinkedToContactDate)}}",
-			"WebVisitSavedId": "{{Activity.Field(WebVisitSavedId)}}",
-		},
-		"pv": Fields{
-			"ActivityId": "{{Activity.Id}}",
-			"ActivityType": "{{Activity.Type}}",
-			"ActivityDate": "{{Activity.CreatedAt}}",
-			"ContactId": "{{Activity.Contact.Id}}",
-			"CampaignId": "{{Activity.Campaign.Id}}",
-			"VisitorId": "{{Activity.Visitor.Id}}",
-			"VisitorExternalId": "{{Activity.Visitor.ExternalId}}",
-			"WebVisitId": "{{Activity.Field(WebVisitId)}}",
-			"Url": "{{Activity.Field(Url)}}",
-			"ReferrerUrl": "{{Activity.Field(ReferrerUrl)}}",
-			"IpAddress": "{{Activity.Field(IpAddress)}}",
-			"IsWebTrackingOptedIn": "{{Activity.Field(IsWebTrackingOptedIn)}}",
-			"ExternalId": "{{Activity.ExternalId}}",
-			"LinkedToContactDate": "{{Activity.Field(LinkedToContactDate)}}",
-			"PageViewSavedId": "{{Activity.Field(PageViewSavedId)}}",
-			"CampaignResponseDate": "{{Activity.CampaignResponse.CreatedAt}}",
-			"CampaignResponseMemberStatus": "{{Activity.CampaignResponse.Field(MemberStatus)}}",
-		},
+		eloquactl export activities --type=EmailOpen --since=2019-01-01 --fields='ActivityDate:{{Activity.CreatedAt}},EmailAddress:{{Activity.Field(EmailAddress)}}'
 
-	}
-*/
+		# Export EmailOpen activities with the built-in EmailOpen field set and an extra field
+		eloquactl export activities --type=EmailOpen --since=2019-01-01 --field-set=EmailOpen --fields='Email:{{Activity.Field(EmailAddress)}}'`)
 )
 
 type Validator interface {
@@ -221,7 +61,9 @@ func (fn ValidatorFunc) Validate() error {
 // ExportActivitiesOptions declare the arguments accepted by the 'export activities' command
 // this struct should have all configurable properties of an export
 type ExportActivitiesOptions struct {
-	Client func() *bulk.BulkClient
+	Client func() (*bulk.BulkClient, error)
+	DryRun *bool
+	Cache  func() (*cache.Cache, error)
 
 	PrintFlags  *cmdutil.PrintFlags
 	ExportFlags *cmdutil.ExportFlags
@@ -236,9 +78,11 @@ type ExportActivitiesOptions struct {
 	//Validate ValidatorFunc
 }
 
-func NewExportActivitiesOptions() *ExportActivitiesOptions {
+func NewExportActivitiesOptions(f *factory.Factory) *ExportActivitiesOptions {
 	return &ExportActivitiesOptions{
-		Client:      initClient,
+		Client:      f.BulkClient,
+		DryRun:      &f.DryRun,
+		Cache:       f.Cache,
 		ExportFlags: cmdutil.NewExportFlags(),
 		PrintFlags:  cmdutil.NewPrintFlags(),
 
@@ -246,8 +90,8 @@ func NewExportActivitiesOptions() *ExportActivitiesOptions {
 	}
 }
 
-func NewCmdExportActivities() *cobra.Command {
-	o := NewExportActivitiesOptions()
+func NewCmdExportActivities(f *factory.Factory) *cobra.Command {
+	o := NewExportActivitiesOptions(f)
 
 	cmd := &cobra.Command{
 		Use:     "activities --type ACTIVITYTYPE",
@@ -255,10 +99,13 @@ func NewCmdExportActivities() *cobra.Command {
 		Short:   "Export Eloqua activities to a file or stdout.",
 		Long:    exportActivitiesLong,
 		Example: exportActivitiesExample,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			o.Complete(cmd)
-			o.Validate()
-			o.Run(cmd)
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(cmd)
 		},
 	}
 	// Add shared flags
@@ -337,32 +184,40 @@ func (p *ExportActivitiesOptions) Validate() error {
 
 // Run executes the command
 func (p *ExportActivitiesOptions) Run(cmd *cobra.Command) error {
-	ctx := context.Background()
-	client := p.Client()
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := p.Client()
+	if err != nil {
+		return err
+	}
+
+	return p.runExport(ctx, client, nil, &output{w: os.Stdout})
+}
+
+// runExport exports to out, it is shared with export jobs.
+func (p *ExportActivitiesOptions) runExport(ctx context.Context, client *bulk.BulkClient, args []string, out *output) error {
 	// if fields are empty, should get the fields via api
 	// fields is a runtime option if not provided
-	fields := Fields{}
-	var keys []string
-	if len(*p.ExportFlags.Fields) == 0 {
+	fields, keys, err := exportFields("activities", p.ExportFlags)
+	if err != nil {
+		return err
+	}
+
+	if len(fields) == 0 {
 		// get fields via api and assign
-		opt := &bulk.ActivityFieldListQueryOptions{ActivityType: p.ActivityType}
-		// TODO: default fields should be cached
-		r, err := client.Activities.ListFields(ctx, opt)
+		md, err := metadata(client, p.Cache)
+		if err != nil {
+			return err
+		}
+
+		r, err := md.ActivityFields(ctx, p.ActivityType)
 		if err != nil {
-			fmt.Printf("Failed to list activity fields for activity type: %v", p.ActivityType)
-			os.Exit(1)
+			return fmt.Errorf("failed to list activity fields for activity type %v: %w", p.ActivityType, err)
 		}
 
 		for _, f := range r.Items {
 			fields[f.InternalName] = f.Statement
 		}
-	} else {
-		k, err := parseFieldsStr(*p.ExportFlags.Fields, &fields)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-		keys = k
 	}
 
 	if len(*p.ExportFlags.Filter) == 0 {
@@ -389,19 +244,15 @@ func (p *ExportActivitiesOptions) Run(cmd *cobra.Command) error {
 		e.MaxRecords = *p.ExportFlags.MaxRecords
 	}
 
-	data, err := json.MarshalIndent(e, "", "    ")
-	if err != nil {
-		return err
+	path := "/activities/exports"
+	if *p.DryRun {
+		return dryRun(path, e)
 	}
 
-	data = append(data, '\n')
-
-	e, err = client.Activities.CreateExport(ctx, e)
+	e, err = createExport(ctx, client, path, e, p.ExportFlags, client.Activities.CreateExport)
 	if err != nil {
 		return err
 	}
 
-	export(ctx, e, &keys, &printer, client)
-
-	return nil
+	return export(ctx, e, &keys, &printer, client, p.ExportFlags, out)
 }
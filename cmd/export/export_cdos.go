@@ -4,10 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strconv"
 	//"encoding/json"
 
 	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/factory"
 	cmdutil "github.com/elqx/eloquactl/pkg/util"
 	"github.com/elqx/eloquactl/pkg/util/templates"
 	"github.com/spf13/cobra"
@@ -25,21 +26,25 @@ var (
 )
 
 type ExportCdosOptions struct {
-	Client      func() *bulk.BulkClient
+	Client      func() (*bulk.BulkClient, error)
+	DryRun      *bool
+	Cache       func() (*cache.Cache, error)
 	PrintFlags  *cmdutil.PrintFlags
 	ExportFlags *cmdutil.ExportFlags
 }
 
-func NewExportCdosOptions() *ExportCdosOptions {
+func NewExportCdosOptions(f *factory.Factory) *ExportCdosOptions {
 	return &ExportCdosOptions{
-		Client:      initClient,
+		Client:      f.BulkClient,
+		DryRun:      &f.DryRun,
+		Cache:       f.Cache,
 		ExportFlags: cmdutil.NewExportFlags(),
 		PrintFlags:  cmdutil.NewPrintFlags(),
 	}
 }
 
-func NewCmdExportCdos() *cobra.Command {
-	o := NewExportCdosOptions()
+func NewCmdExportCdos(f *factory.Factory) *cobra.Command {
+	o := NewExportCdosOptions(f)
 
 	cmd := &cobra.Command{
 		Use:     "cdos <NAME>",
@@ -47,14 +52,17 @@ func NewCmdExportCdos() *cobra.Command {
 		Short:   "Export CDO to a file or stdout.",
 		Long:    exportCdosLong,
 		Example: exportCdosExample,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
-				cmdutil.Er("export cdos needs a cdo name or id for the command")
+				return cmdutil.ValidationErrorf("export cdos needs a cdo name or id for the command")
 			}
 
 			o.Complete(cmd)
-			o.Validate(cmd)
-			o.Run(cmd, args)
+			if err := o.Validate(cmd); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(cmd, args)
 		},
 	}
 	// Add shared flags
@@ -97,54 +105,43 @@ func (p *ExportCdosOptions) Validate(cmd *cobra.Command) error {
 }
 
 func (p *ExportCdosOptions) Run(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-	client := p.Client()
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := p.Client()
+	if err != nil {
+		return err
+	}
+
+	return p.runExport(ctx, client, args, &output{w: os.Stdout})
+}
 
-	var parentId int
-	// check if args[0] is numeric or string
-	parentId, err := strconv.Atoi(args[0])
+// runExport exports to out, it is shared with export jobs.
+func (p *ExportCdosOptions) runExport(ctx context.Context, client *bulk.BulkClient, args []string, out *output) error {
+	md, err := metadata(client, p.Cache)
 	if err != nil {
-		// name is given, find id
-		// TODO: check cache first
-		cdos, err := client.Cdos.List(ctx)
-		if err != nil {
-			cmdutil.Er("Failed listing CDOs")
-		}
+		return err
+	}
 
-		for _, cdo := range cdos.Items {
-			if cdo.Name == args[0] {
-				// skipping /customObjects/ prefix
-				parentId, err = strconv.Atoi(cdo.Uri[15:])
-				if err != nil {
-					cmdutil.Er("Error extracting custom object id")
-				}
-			}
-		}
+	parentId, err := md.CdoID(ctx, args[0])
+	if err != nil {
+		return err
 	}
 
-	fieldsStr, _ := cmd.Flags().GetString("fields")
-	fields := Fields{}
-	var keys []string
-	if fieldsStr == "" {
+	fields, keys, err := exportFields("cdos", p.ExportFlags)
+	if err != nil {
+		return err
+	}
+
+	if len(fields) == 0 {
 		// getting default fields
-		// TODO: default fields should be cached
-		r, err := client.Cdos.ListFields(ctx, parentId)
+		r, err := md.CdoFields(ctx, parentId)
 		if err != nil {
-			fmt.Println("Failed getting cdo fields definitions")
-			os.Exit(1)
+			return fmt.Errorf("failed getting cdo fields definitions: %w", err)
 		}
 
 		for _, f := range r.Items {
 			fields[f.InternalName] = f.Statement
 		}
-
-	} else {
-		k, err := parseFieldsStr(fieldsStr, &fields)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-		keys = k
 	}
 
 	printer, err := p.PrintFlags.ToPrinter()
@@ -168,12 +165,17 @@ func (p *ExportCdosOptions) Run(cmd *cobra.Command, args []string) error {
 		e.MaxRecords = *p.ExportFlags.MaxRecords
 	}
 
-	e, err = client.Cdos.CreateExport(ctx, parentId, e)
+	path := fmt.Sprintf("/customObjects/%d/exports", parentId)
+	if *p.DryRun {
+		return dryRun(path, e)
+	}
+
+	e, err = createExport(ctx, client, path, e, p.ExportFlags, func(ctx context.Context, e *bulk.Export) (*bulk.Export, error) {
+		return client.Cdos.CreateExport(ctx, parentId, e)
+	})
 	if err != nil {
 		return err
 	}
 
-	export(ctx, e, &keys, &printer, client)
-
-	return nil
+	return export(ctx, e, &keys, &printer, client, p.ExportFlags, out)
 }
@@ -6,6 +6,8 @@ import (
 	"os"
 
 	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/factory"
 	cmdutil "github.com/elqx/eloquactl/pkg/util"
 	"github.com/elqx/eloquactl/pkg/util/templates"
 	"github.com/spf13/cobra"
@@ -29,7 +31,9 @@ var (
 )
 
 type ExportContactsOptions struct {
-	Client func() *bulk.BulkClient
+	Client func() (*bulk.BulkClient, error)
+	DryRun *bool
+	Cache  func() (*cache.Cache, error)
 
 	ExportFlags *cmdutil.ExportFlags
 	PrintFlags  *cmdutil.PrintFlags
@@ -42,26 +46,31 @@ type ExportContactsOptions struct {
 	UpdatedAfter   string
 }
 
-func NewExportContactsOptions() *ExportContactsOptions {
+func NewExportContactsOptions(f *factory.Factory) *ExportContactsOptions {
 	return &ExportContactsOptions{
-		Client:      initClient,
+		Client:      f.BulkClient,
+		DryRun:      &f.DryRun,
+		Cache:       f.Cache,
 		ExportFlags: cmdutil.NewExportFlags(),
 		PrintFlags:  cmdutil.NewPrintFlags(),
 	}
 }
 
-func NewCmdExportContacts() *cobra.Command {
-	o := NewExportContactsOptions()
+func NewCmdExportContacts(f *factory.Factory) *cobra.Command {
+	o := NewExportContactsOptions(f)
 	cmd := &cobra.Command{
 		Use:     "contacts",
 		Aliases: []string{"contact"},
 		Short:   "Export Eloqua contacts to a file or stdout",
 		Long:    exportContactsLong,
 		Example: exportContactsExample,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			o.Complete(cmd)
-			o.Validate()
-			o.Run(cmd)
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(cmd)
 		},
 	}
 
@@ -139,31 +148,40 @@ func (o *ExportContactsOptions) Validate() error {
 }
 
 func (o *ExportContactsOptions) Run(cmd *cobra.Command) error {
-	ctx := context.Background()
-	client := o.Client()
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	return o.runExport(ctx, client, nil, &output{w: os.Stdout})
+}
+
+// runExport exports to out, it is shared with export jobs.
+func (o *ExportContactsOptions) runExport(ctx context.Context, client *bulk.BulkClient, args []string, out *output) error {
 	// if fields are empty, should get the fields via api
 	// fields is a runtime option if not provided
-	fields := Fields{}
-	var keys []string
-	if len(*o.ExportFlags.Fields) == 0 {
+	fields, keys, err := exportFields("contacts", o.ExportFlags)
+	if err != nil {
+		return err
+	}
+
+	if len(fields) == 0 {
 		// get fields via api and assign
-		// TODO: default fields should be cached
-		r, err := client.Contacts.GetFields(ctx)
+		md, err := metadata(client, o.Cache)
 		if err != nil {
-			fmt.Println("Failed to list contact fields")
-			os.Exit(1)
+			return err
+		}
+
+		r, err := md.ContactFields(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list contact fields: %w", err)
 		}
 
 		for _, f := range r.Items {
 			fields[f.InternalName] = f.Statement
 		}
-	} else {
-		k, err := parseFieldsStr(*o.ExportFlags.Fields, &fields)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-		keys = k
 	}
 
 	if len(*o.ExportFlags.Filter) == 0 {
@@ -189,12 +207,15 @@ func (o *ExportContactsOptions) Run(cmd *cobra.Command) error {
 		MaxRecords: *o.ExportFlags.MaxRecords,
 	}
 
-	e, err = client.Contacts.CreateExport(ctx, e)
+	path := "/contacts/exports"
+	if *o.DryRun {
+		return dryRun(path, e)
+	}
+
+	e, err = createExport(ctx, client, path, e, o.ExportFlags, client.Contacts.CreateExport)
 	if err != nil {
 		return err
 	}
 
-	export(ctx, e, &keys, &printer, client)
-
-	return nil
+	return export(ctx, e, &keys, &printer, client, o.ExportFlags, out)
 }
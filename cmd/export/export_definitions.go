@@ -0,0 +1,35 @@
+package export
+
+import (
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportDefinitionsLong = templates.LongDesc(`
+		Manage Bulk API export definitions of contacts, accounts, activities and CDOs.
+
+		Every export run creates an export definition, unless --reuse finds an existing one.
+		Definitions are deleted after the export unless --keep-definition is given.`)
+)
+
+func NewCmdExportDefinitions(f *factory.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "definitions SUBCOMMAND",
+		Aliases: []string{"definition", "defs"},
+		Short:   "Manage export definitions",
+		Long:    exportDefinitionsLong,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdutil.ValidationErrorf("you must specify a subcommand. See 'eloquactl export definitions -h' for help and examples")
+		},
+	}
+
+	// create subcommands
+	cmd.AddCommand(NewCmdExportDefinitionsList(f))
+	cmd.AddCommand(NewCmdExportDefinitionsGet(f))
+	cmd.AddCommand(NewCmdExportDefinitionsDelete(f))
+
+	return cmd
+}
@@ -0,0 +1,61 @@
+package export
+
+import (
+	"fmt"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportDefinitionsDeleteExample = templates.Examples(`
+		# Delete export definitions given their uris
+		eloquactl export definitions delete /contacts/exports/123 /customObjects/15/exports/456`)
+)
+
+type ExportDefinitionsDeleteOptions struct {
+	Client func() (*bulk.BulkClient, error)
+}
+
+func NewCmdExportDefinitionsDelete(f *factory.Factory) *cobra.Command {
+	o := &ExportDefinitionsDeleteOptions{
+		Client: f.BulkClient,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "delete <URI>...",
+		Aliases: []string{"rm"},
+		Short:   "Delete export definitions",
+		Example: exportDefinitionsDeleteExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return cmdutil.ValidationErrorf("export definitions delete needs the uri of at least one definition")
+			}
+
+			return o.Run(args)
+		},
+	}
+
+	return cmd
+}
+
+func (o *ExportDefinitionsDeleteOptions) Run(uris []string) error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	for _, uri := range uris {
+		if err := bulkapi.DeleteDefinition(ctx, client, uri); err != nil {
+			return fmt.Errorf("failed to delete export definition %s: %w", uri, err)
+		}
+		fmt.Printf("export definition %s deleted\n", uri)
+	}
+	return nil
+}
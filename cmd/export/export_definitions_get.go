@@ -0,0 +1,87 @@
+package export
+
+import (
+	"os"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/printers"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportDefinitionsGetExample = templates.Examples(`
+		# Get an export definition given its uri
+		eloquactl export definitions get /contacts/exports/123`)
+)
+
+type ExportDefinitionsGetOptions struct {
+	Client     func() (*bulk.BulkClient, error)
+	PrintFlags *cmdutil.PrintFlags
+}
+
+func NewExportDefinitionsGetOptions(f *factory.Factory) *ExportDefinitionsGetOptions {
+	outputFormat := "json"
+	printFlags := cmdutil.NewPrintFlags()
+	printFlags.OutputFormat = &outputFormat
+
+	return &ExportDefinitionsGetOptions{
+		Client:     f.BulkClient,
+		PrintFlags: printFlags,
+	}
+}
+
+func NewCmdExportDefinitionsGet(f *factory.Factory) *cobra.Command {
+	o := NewExportDefinitionsGetOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "get <URI>",
+		Short:   "Get an export definition",
+		Example: exportDefinitionsGetExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return cmdutil.ValidationErrorf("export definitions get needs the uri of a definition")
+			}
+
+			if err := o.PrintFlags.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(args[0])
+		},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+func (o *ExportDefinitionsGetOptions) Run(uri string) error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	e, err := bulkapi.GetExport(ctx, client, uri)
+	if err != nil {
+		return err
+	}
+
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+
+	// table and ndj printers work with lists
+	if _, ok := printer.(*printers.JsonPrinter); !ok {
+		w := printers.NewTabWriter(os.Stdout)
+		defer w.Flush()
+		return printer.PrintResource([]bulk.Export{*e}, w)
+	}
+	return printer.PrintResource(e, os.Stdout)
+}
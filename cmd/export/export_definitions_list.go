@@ -0,0 +1,171 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/printers"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportDefinitionsListLong = templates.LongDesc(`
+		List export definitions.
+
+		Without --type the definitions of all entity types are listed, including
+		the definitions of every CDO.`)
+
+	exportDefinitionsListExample = templates.Examples(`
+		# List all export definitions
+		eloquactl export definitions list
+
+		# List contact export definitions
+		eloquactl export definitions list --type=contacts
+
+		# List export definitions of a CDO given its name
+		eloquactl export definitions list --type=cdos --cdo=NAME
+
+		# List export definitions with a name matching the search
+		eloquactl export definitions list --search="name='eloquactl*'"`)
+)
+
+type ExportDefinitionsListOptions struct {
+	Client     func() (*bulk.BulkClient, error)
+	Cache      func() (*cache.Cache, error)
+	PrintFlags *cmdutil.PrintFlags
+
+	Type   string
+	Cdo    string
+	Search string
+}
+
+func NewExportDefinitionsListOptions(f *factory.Factory) *ExportDefinitionsListOptions {
+	return &ExportDefinitionsListOptions{
+		Client:     f.BulkClient,
+		Cache:      f.Cache,
+		PrintFlags: cmdutil.NewPrintFlags(),
+	}
+}
+
+func NewCmdExportDefinitionsList(f *factory.Factory) *cobra.Command {
+	o := NewExportDefinitionsListOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List export definitions",
+		Long:    exportDefinitionsListLong,
+		Example: exportDefinitionsListExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run()
+		},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+	cmd.Flags().StringVar(&o.Type, "type", "", "Entity type of the definitions. One of: contacts|accounts|activities|cdos.")
+	cmd.Flags().StringVar(&o.Cdo, "cdo", "", "Name or id of the CDO when --type=cdos. Defaults to all CDOs.")
+	cmd.Flags().StringVar(&o.Search, "search", "", "Bulk API search expression, e.g. name='Weekly*'.")
+
+	return cmd
+}
+
+func (o *ExportDefinitionsListOptions) Validate() error {
+	switch o.Type {
+	case "", "contacts", "accounts", "activities":
+		if o.Cdo != "" {
+			return fmt.Errorf("--cdo can only be used with --type=cdos")
+		}
+	case "cdos":
+	default:
+		return fmt.Errorf("invalid type %q, expected one of: contacts|accounts|activities|cdos", o.Type)
+	}
+
+	return o.PrintFlags.Validate()
+}
+
+func (o *ExportDefinitionsListOptions) Run() error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	paths, err := o.paths(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	var exports []bulk.Export
+	for _, path := range paths {
+		e, err := bulkapi.ListExports(ctx, client, path, o.Search)
+		if err != nil {
+			return fmt.Errorf("failed to list export definitions %s: %w", path, err)
+		}
+		exports = append(exports, e...)
+	}
+
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	w := printers.NewTabWriter(os.Stdout)
+	defer w.Flush()
+
+	return printer.PrintResource(exports, w)
+}
+
+// paths returns the export endpoints to list.
+func (o *ExportDefinitionsListOptions) paths(ctx context.Context, client *bulk.BulkClient) ([]string, error) {
+	var paths []string
+	if o.Type != "cdos" {
+		for _, entity := range []string{"contacts", "accounts", "activities"} {
+			if o.Type == "" || o.Type == entity {
+				path, _ := bulkapi.ExportsPath(entity, 0)
+				paths = append(paths, path)
+			}
+		}
+		if o.Type != "" {
+			return paths, nil
+		}
+	}
+
+	md, err := metadata(client, o.Cache)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Cdo != "" {
+		id, err := md.CdoID(ctx, o.Cdo)
+		if err != nil {
+			return nil, err
+		}
+		path, _ := bulkapi.ExportsPath("cdos", id)
+		return append(paths, path), nil
+	}
+
+	cdos, err := md.Cdos(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cdos: %w", err)
+	}
+	for _, cdo := range cdos.Items {
+		id, err := bulkapi.UriID(cdo.Uri)
+		if err != nil {
+			return nil, err
+		}
+		path, _ := bulkapi.ExportsPath("cdos", id)
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
@@ -0,0 +1,374 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/printers"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"gopkg.in/yaml.v2"
+)
+
+// ExportJob is an export described in a YAML or JSON file, so that recurring
+// exports can be kept in version control. A file may hold several jobs as
+// YAML documents separated by "---".
+type ExportJob struct {
+	// Name identifies the job in the summary, defaults to the file name.
+	Name string `yaml:"name,omitempty"`
+	// Entity is one of contacts, accounts, activities, cdos or scores.
+	Entity string `yaml:"entity"`
+
+	// ActivityType, Since and Until select activities.
+	ActivityType string `yaml:"activityType,omitempty"`
+	Since        string `yaml:"since,omitempty"`
+	Until        string `yaml:"until,omitempty"`
+	// Cdo is the name or id of the custom object to export.
+	Cdo string `yaml:"cdo,omitempty"`
+	// Model is the name of the lead scoring model to export.
+	Model string `yaml:"model,omitempty"`
+
+	// Definition is the name of the export definition, defaults to a name
+	// derived from the entity, fields and filter.
+	Definition string            `yaml:"definition,omitempty"`
+	Reuse      bool              `yaml:"reuse,omitempty"`
+	FieldSet   string            `yaml:"fieldSet,omitempty"`
+	Fields     map[string]string `yaml:"fields,omitempty"`
+	Filter     string            `yaml:"filter,omitempty"`
+	MaxRecords uint              `yaml:"maxRecords,omitempty"`
+	UTC        *bool             `yaml:"utc,omitempty"`
+
+	Staging struct {
+		AutoDeleteDuration    string `yaml:"autoDeleteDuration,omitempty"`
+		DataRetentionDuration string `yaml:"dataRetentionDuration,omitempty"`
+	} `yaml:"staging,omitempty"`
+
+	Output struct {
+		// Format is one of json, ndj or table.
+		Format string `yaml:"format,omitempty"`
+		// Destination is the file to write to, stdout if empty or "-".
+		Destination string `yaml:"destination,omitempty"`
+	} `yaml:"output,omitempty"`
+
+	file string
+}
+
+// exporter is implemented by the options of every export command.
+type exporter interface {
+	runExport(ctx context.Context, client *bulk.BulkClient, args []string, out *output) error
+}
+
+// jobResult is a line of the summary.
+type jobResult struct {
+	job      *ExportJob
+	rows     int
+	duration time.Duration
+	err      error
+	skipped  bool
+}
+
+var jobEntities = map[string]string{
+	"contact":    "contacts",
+	"contacts":   "contacts",
+	"account":    "accounts",
+	"accounts":   "accounts",
+	"activity":   "activities",
+	"activities": "activities",
+	"cdo":        "cdos",
+	"cdos":       "cdos",
+	"score":      "scores",
+	"scores":     "scores",
+}
+
+// loadJobs reads the export jobs from the files.
+func loadJobs(files []string) ([]*ExportJob, error) {
+	var jobs []*ExportJob
+	for _, file := range files {
+		var data []byte
+		var err error
+		if file == "-" {
+			data, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			data, err = ioutil.ReadFile(file)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var fileJobs []*ExportJob
+		d := yaml.NewDecoder(strings.NewReader(string(data)))
+		for {
+			j := &ExportJob{}
+			if err := d.Decode(j); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("%s: %v", file, err)
+			}
+			j.file = file
+			fileJobs = append(fileJobs, j)
+		}
+
+		for i, j := range fileJobs {
+			if j.Name != "" {
+				continue
+			}
+			j.Name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+			if len(fileJobs) > 1 {
+				j.Name = fmt.Sprintf("%s-%d", j.Name, i+1)
+			}
+		}
+		jobs = append(jobs, fileJobs...)
+	}
+
+	if len(jobs) == 0 {
+		return nil, errors.New("no export jobs found")
+	}
+	return jobs, nil
+}
+
+// validateJobs checks the jobs before any of them runs.
+func validateJobs(jobs []*ExportJob, parallel int) error {
+	names := map[string]string{}
+	destinations := map[string]string{}
+	stdout := 0
+
+	for _, j := range jobs {
+		if f, ok := names[j.Name]; ok {
+			return fmt.Errorf("%s: job name %q is already used in %s", j.file, j.Name, f)
+		}
+		names[j.Name] = j.file
+
+		entity, ok := jobEntities[j.Entity]
+		if !ok {
+			return fmt.Errorf("%s: job %s: invalid entity %q, expected one of: contacts|accounts|activities|cdos|scores", j.file, j.Name, j.Entity)
+		}
+		j.Entity = entity
+
+		switch {
+		case j.Entity == "activities" && j.ActivityType == "":
+			return fmt.Errorf("%s: job %s: activityType is required", j.file, j.Name)
+		case j.Entity == "cdos" && j.Cdo == "":
+			return fmt.Errorf("%s: job %s: cdo is required", j.file, j.Name)
+		case j.Entity == "scores" && j.Model == "":
+			return fmt.Errorf("%s: job %s: model is required", j.file, j.Name)
+		}
+
+		switch j.Output.Format {
+		case "", "json", "ndj", "table":
+		default:
+			return fmt.Errorf("%s: job %s: invalid output format %q, expected one of: json|ndj|table", j.file, j.Name, j.Output.Format)
+		}
+
+		dest := j.Output.Destination
+		if dest == "" || dest == "-" {
+			stdout++
+			continue
+		}
+		if other, ok := destinations[filepath.Clean(dest)]; ok {
+			return fmt.Errorf("%s: job %s: destination %s is also used by job %s", j.file, j.Name, dest, other)
+		}
+		destinations[filepath.Clean(dest)] = j.Name
+	}
+
+	if parallel > 1 && stdout > 1 {
+		return errors.New("jobs running in parallel can not all write to stdout, set output.destination or --parallel=1")
+	}
+	return nil
+}
+
+// exporter returns the options of the export command for the job entity,
+// set from the job, and the command arguments.
+func (j *ExportJob) exporter(f *factory.Factory) (exporter, []string, error) {
+	ef := cmdutil.NewExportFlags()
+	*ef.Name = j.Definition
+	*ef.Reuse = j.Reuse
+	*ef.Filter = j.Filter
+	*ef.MaxRecords = j.MaxRecords
+	if j.UTC != nil {
+		*ef.AreSystemTimestampsInUTC = *j.UTC
+	}
+	if j.Staging.AutoDeleteDuration != "" {
+		*ef.StagingFlags.AutoDeleteDuration = j.Staging.AutoDeleteDuration
+	}
+	if j.Staging.DataRetentionDuration != "" {
+		*ef.StagingFlags.DataRetentionDuration = j.Staging.DataRetentionDuration
+	}
+
+	ef.JobFields = j.Fields
+	*ef.FieldSet = j.FieldSet
+
+	pf := cmdutil.NewPrintFlags()
+	*pf.OutputFormat = j.Output.Format
+
+	switch j.Entity {
+	case "contacts":
+		o := NewExportContactsOptions(f)
+		o.ExportFlags, o.PrintFlags = ef, pf
+		return o, nil, o.Validate()
+	case "accounts":
+		o := NewExportAccountsOptions(f)
+		o.ExportFlags, o.PrintFlags = ef, pf
+		return o, nil, o.Validate()
+	case "activities":
+		o := NewExportActivitiesOptions(f)
+		o.ExportFlags, o.PrintFlags = ef, pf
+		o.ActivityType, o.Since, o.Until = j.ActivityType, j.Since, j.Until
+		o.Complete(nil)
+		// an explicit filter replaces the one built from the type and dates
+		if j.Filter != "" {
+			*o.ExportFlags.Filter = j.Filter
+		}
+		return o, nil, o.Validate()
+	case "cdos":
+		o := NewExportCdosOptions(f)
+		o.ExportFlags, o.PrintFlags = ef, pf
+		return o, []string{j.Cdo}, o.Validate(nil)
+	default:
+		o := NewExportScoresOptions(f)
+		o.ExportFlags, o.PrintFlags = ef, pf
+		return o, []string{j.Model}, o.Validate()
+	}
+}
+
+// run runs the job, writing to its destination. A destination the job
+// failed to write anything to is removed.
+func (j *ExportJob) run(ctx context.Context, client *bulk.BulkClient, e exporter, args []string, dryRun bool) (rows int, err error) {
+	out := &output{w: os.Stdout}
+
+	// the dry run prints the definition to stdout only
+	if dest := j.Output.Destination; dest != "" && dest != "-" && !dryRun {
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return 0, err
+		}
+
+		file, err := os.Create(dest)
+		if err != nil {
+			return 0, err
+		}
+		defer func() {
+			file.Close()
+			if err != nil && rows == 0 {
+				os.Remove(dest)
+			}
+		}()
+		out.w = file
+	}
+
+	err = e.runExport(ctx, client, args, out)
+	return out.rows, err
+}
+
+// runJobs runs the jobs, up to parallel at once, and prints a summary to stderr.
+func runJobs(ctx context.Context, f *factory.Factory, jobs []*ExportJob, parallel int) error {
+	exporters := make([]exporter, len(jobs))
+	args := make([][]string, len(jobs))
+	for i, j := range jobs {
+		e, a, err := j.exporter(f)
+		if err != nil {
+			return cmdutil.ValidationErrorf("%s: job %s: %v", j.file, j.Name, err)
+		}
+		exporters[i], args[i] = e, a
+	}
+
+	client, err := f.BulkClient()
+	if err != nil {
+		return err
+	}
+
+	results := make([]jobResult, len(jobs))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+
+	for i, j := range jobs {
+		sem <- struct{}{}
+		if ctx.Err() != nil {
+			results[i] = jobResult{job: j, skipped: true}
+			<-sem
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, j *ExportJob) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			start := time.Now()
+			rows, err := j.run(ctx, client, exporters[i], args[i], f.DryRun)
+			results[i] = jobResult{job: j, rows: rows, duration: time.Since(start), err: err}
+		}(i, j)
+	}
+	wg.Wait()
+
+	printSummary(results)
+
+	return jobsError(ctx, results)
+}
+
+// printSummary prints a line per job to stderr.
+func printSummary(results []jobResult) {
+	w := printers.NewTabWriter(os.Stderr)
+	fmt.Fprintln(w, "JOB\tENTITY\tSTATUS\tROWS\tDURATION\tDESTINATION\tERROR")
+	for _, r := range results {
+		status, msg := "ok", ""
+		_, partial := r.err.(*cmdutil.PartialError)
+		switch {
+		case r.skipped:
+			status = "skipped"
+		case partial:
+			status, msg = "warning", r.err.Error()
+		case r.err != nil:
+			status, msg = "failed", r.err.Error()
+		}
+
+		dest := r.job.Output.Destination
+		if dest == "" {
+			dest = "-"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%v\t%s\t%s\n", r.job.Name, r.job.Entity, status, r.rows, r.duration.Round(time.Second), dest, msg)
+	}
+	w.Flush()
+}
+
+// jobsError returns the error of the whole run. The error of a failed job is
+// returned as is when no job succeeded, so that the exit code tells the cause.
+func jobsError(ctx context.Context, results []jobResult) error {
+	if ctx.Err() != nil {
+		return &cmdutil.InterruptedError{}
+	}
+
+	var first error
+	failed, warnings := 0, 0
+	for _, r := range results {
+		if _, ok := r.err.(*cmdutil.PartialError); ok {
+			warnings++
+		} else if r.err != nil {
+			failed++
+			if first == nil {
+				first = r.err
+			}
+		}
+	}
+
+	switch {
+	case failed == 0 && warnings == 0:
+		return nil
+	case failed == 0:
+		return &cmdutil.PartialError{Err: fmt.Errorf("%d of %d export jobs finished with warnings", warnings, len(results))}
+	case failed == len(results):
+		return first
+	default:
+		return &cmdutil.PartialError{Err: fmt.Errorf("%d of %d export jobs failed", failed, len(results))}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 
 	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/factory"
 	cmdutil "github.com/elqx/eloquactl/pkg/util"
 	"github.com/elqx/eloquactl/pkg/util/templates"
 	"github.com/spf13/cobra"
@@ -26,22 +28,26 @@ var (
 )
 
 type ExportScoresOptions struct {
-	Client func() *bulk.BulkClient
+	Client func() (*bulk.BulkClient, error)
+	DryRun *bool
+	Cache  func() (*cache.Cache, error)
 
 	ExportFlags *cmdutil.ExportFlags
 	PrintFlags  *cmdutil.PrintFlags
 }
 
-func NewExportScoresOptions() *ExportScoresOptions {
+func NewExportScoresOptions(f *factory.Factory) *ExportScoresOptions {
 	return &ExportScoresOptions{
-		Client:      initClient,
+		Client:      f.BulkClient,
+		DryRun:      &f.DryRun,
+		Cache:       f.Cache,
 		ExportFlags: cmdutil.NewExportFlags(),
 		PrintFlags:  cmdutil.NewPrintFlags(),
 	}
 }
 
-func NewCmdExportScores() *cobra.Command {
-	o := NewExportScoresOptions()
+func NewCmdExportScores(f *factory.Factory) *cobra.Command {
+	o := NewExportScoresOptions(f)
 
 	cmd := &cobra.Command{
 		Use:     "scores",
@@ -49,10 +55,13 @@ func NewCmdExportScores() *cobra.Command {
 		Short:   "Export Eloqua lead scores to a file or stdout",
 		Long:    exportScoresLong,
 		Example: exportScoresExample,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			o.Complete(cmd)
-			o.Validate()
-			o.Run(cmd, args)
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(cmd, args)
 		},
 	}
 
@@ -83,19 +92,35 @@ func (o *ExportScoresOptions) Validate() error {
 }
 
 func (o *ExportScoresOptions) Run(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-	client := o.Client()
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	return o.runExport(ctx, client, args, &output{w: os.Stdout})
+}
+
+// runExport exports to out, it is shared with export jobs.
+func (o *ExportScoresOptions) runExport(ctx context.Context, client *bulk.BulkClient, args []string, out *output) error {
 	// if fields are empty, should get the fields via api
 	// fields is a runtime option if not provided
-	fields := Fields{}
-	var keys []string
-	if len(*o.ExportFlags.Fields) == 0 {
+	fields, keys, err := exportFields("scores", o.ExportFlags)
+	if err != nil {
+		return err
+	}
+
+	if len(fields) == 0 {
 		// get fields via api and assign
-		// TODO: default fields should be cached
-		r, err := client.Contacts.ListLeadModels(ctx)
+		md, err := metadata(client, o.Cache)
 		if err != nil {
-			fmt.Println("Failed to list lead scoring models")
-			os.Exit(1)
+			return err
+		}
+
+		r, err := md.LeadModels(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list lead scoring models: %w", err)
 		}
 
 		for _, model := range r.Items {
@@ -108,16 +133,8 @@ func (o *ExportScoresOptions) Run(cmd *cobra.Command, args []string) error {
 		}
 
 		if len(fields) == 0 {
-			fmt.Printf("Lead Scoring Model %v does not exist", args[0])
-			os.Exit(1)
-		}
-	} else {
-		k, err := parseFieldsStr(*o.ExportFlags.Fields, &fields)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return fmt.Errorf("lead scoring model %v does not exist", args[0])
 		}
-		keys = k
 	}
 
 	printer, err := o.PrintFlags.ToPrinter()
@@ -143,12 +160,15 @@ func (o *ExportScoresOptions) Run(cmd *cobra.Command, args []string) error {
 		e.MaxRecords = *o.ExportFlags.MaxRecords
 	}
 
-	e, err = client.Contacts.CreateExport(ctx, e)
+	path := "/contacts/exports"
+	if *o.DryRun {
+		return dryRun(path, e)
+	}
+
+	e, err = createExport(ctx, client, path, e, o.ExportFlags, client.Contacts.CreateExport)
 	if err != nil {
 		return err
 	}
 
-	export(ctx, e, &keys, &printer, client)
-
-	return nil
+	return export(ctx, e, &keys, &printer, client, o.ExportFlags, out)
 }
@@ -1,23 +1,172 @@
 package feed
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
 	"github.com/spf13/cobra"
 )
 
-func NewCmdFeed() *cobra.Command {
+const (
+	contactImportsPath = "/contacts/imports"
+)
+
+var (
+	feedLong = templates.LongDesc(`
+		Feed contacts into a campaign using an App Cloud feeder service instance.
+
+		The contacts are read from CSV files with a header row, JSON arrays of objects or
+		newline delimited JSON. Columns are mapped to the contact fields with the same
+		name or internal name, unless --fields is given. Contacts are matched by the email
+		address column, unless --identifier is given.
+
+		A contact import is created for the instance, the contacts are uploaded and synced
+		into the campaign, and the result of every row is printed. The exit code is 6 when
+		some rows were rejected.`)
+
+	feedExample = templates.Examples(`
+		# Feed contacts into a campaign from the feeder instance a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f
+		eloquactl feed --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f -f contacts.csv
+
+		# Map the columns to contact fields
+		eloquactl feed --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f -f audience.csv \
+		  --fields='email:{{Contact.Field(C_EmailAddress)}},country:{{Contact.Field(C_Country)}}'
+
+		# Print the import definition without creating it
+		eloquactl feed --instance=a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f -f contacts.csv --dry-run`)
+)
+
+type FeedOptions struct {
+	Client        func() (*bulk.BulkClient, error)
+	Cache         func() (*cache.Cache, error)
+	DryRun        *bool
+	ImportFlags   *cmdutil.ImportFlags
+	FileNameFlags *cmdutil.FileNameFlags
+	PrintFlags    *cmdutil.PrintFlags
+
+	Instance string
+}
+
+func NewFeedOptions(f *factory.Factory) *FeedOptions {
+	return &FeedOptions{
+		Client:        f.BulkClient,
+		Cache:         f.Cache,
+		DryRun:        &f.DryRun,
+		ImportFlags:   cmdutil.NewImportFlags(),
+		FileNameFlags: cmdutil.NewFileNameFlags(),
+		PrintFlags:    cmdutil.NewPrintFlags(),
+	}
+}
+
+func NewCmdFeed(f *factory.Factory) *cobra.Command {
+	o := NewFeedOptions(f)
+
 	cmd := &cobra.Command{
-		Use:   "feed --instance=...",
-		Short: "Feed contacts into a campaign using the specified feeder instance.",
-		Long:  "Feed contacts into a campaign using the specified feeder instance.",
-		Example: `  # Feed contacts into a campaign from the feeder instance a12d53dd1
-  eloquactl feed --instance=a12d53dd1
-		`,
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("feeding...")
+		Use:     "feed --instance=INSTANCE -f FILENAME",
+		Short:   "Feed contacts into a campaign using the specified feeder instance.",
+		Long:    feedLong,
+		Example: feedExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run()
 		},
 	}
 
+	o.ImportFlags.AddFlags(cmd)
+	o.FileNameFlags.AddFlags(cmd)
+	o.PrintFlags.AddFlags(cmd)
+	cmd.Flags().StringVarP(&o.Instance, "instance", "i", "", "The id of the feeder service instance.")
+
 	return cmd
 }
+
+func (o *FeedOptions) Validate() error {
+	if err := cmdutil.ValidateInstance(o.Instance); err != nil {
+		return err
+	}
+
+	if len(*o.FileNameFlags.FileNames) == 0 {
+		return errors.New("you must specify the contacts to feed with -f")
+	}
+
+	if err := o.ImportFlags.Validate(); err != nil {
+		return err
+	}
+
+	return o.PrintFlags.Validate()
+}
+
+func (o *FeedOptions) Run() error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+
+	files, err := o.FileNameFlags.Files(".csv", ".json", ".ndj")
+	if err != nil {
+		return err
+	}
+
+	columns, records, err := cmdutil.ReadRecordFiles(files)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return cmdutil.ValidationErrorf("no contacts to feed")
+	}
+
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	c, err := o.Cache()
+	if err != nil {
+		return err
+	}
+
+	md := &bulkapi.Metadata{Client: client, Cache: c}
+	fields, identifier, err := cmdutil.ContactImportFields(ctx, md, columns, o.ImportFlags)
+	if err != nil {
+		return err
+	}
+
+	name := *o.ImportFlags.Name
+	if name == "" {
+		name = "eloquactl feed " + o.Instance
+	}
+
+	i := &bulkapi.Import{
+		Name:                             name,
+		Fields:                           fields,
+		IdentifierFieldName:              identifier,
+		IsUpdatingMultipleMatchedRecords: *o.ImportFlags.IsUpdatingMultipleMatchedRecords,
+		UpdateRule:                       *o.ImportFlags.UpdateRule,
+		AutoDeleteDuration:               *o.ImportFlags.StagingFlags.AutoDeleteDuration,
+		DataRetentionDuration:            *o.ImportFlags.StagingFlags.DataRetentionDuration,
+		SyncActions: []bulkapi.SyncAction{
+			{Destination: bulkapi.FeederDestination(o.Instance), Action: bulkapi.SyncActionAdd},
+		},
+	}
+
+	if *o.DryRun {
+		return cmdutil.PrintDryRun("POST", contactImportsPath, i)
+	}
+
+	results, err := cmdutil.RunImport(ctx, client, contactImportsPath, i, records, o.ImportFlags.SyncFlags, *o.ImportFlags.KeepDefinition)
+	if err != nil {
+		return err
+	}
+
+	return cmdutil.PrintImportResults(results, o.PrintFlags, func(imported, total int) string {
+		return fmt.Sprintf("Fed %d of %d contacts into feeder instance %s", imported, total, o.Instance)
+	})
+}
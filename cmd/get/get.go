@@ -23,71 +23,42 @@ package get
 
 import (
 	//	"context"
-	"fmt"
 	//"errors"
 	//"strconv"
 	//"time"
-	"strings"
 	//"regexp"
 	//"os"
 
-	"github.com/elqx/eloqua-go/eloqua/bulk"
-	"github.com/elqx/eloqua-go/eloqua/pkg/auth"
-	"github.com/elqx/eloqua-go/eloqua/rest"
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	//"github.com/elqx/eloquactl/pkg/printers"
 	//cmdutil "github.com/elqx/eloquactl/pkg/util"
 )
 
-const (
-	apiVersion = "2.0"
-)
-
 // type Fields map[string]string
-func NewCmdGet() *cobra.Command {
+func NewCmdGet(f *factory.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "get",
 		Short:   "get a resource from Eloqua",
 		Long:    "",
 		Example: "",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("Getting...")
-			fmt.Println("Done.")
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdutil.ValidationErrorf("you must specify the type of resource to get. See 'eloquactl get -h' for help and examples")
 		},
 	}
 
 	// create subcommands
-	cmd.AddCommand(NewCmdGetCampaigns())
-	cmd.AddCommand(NewCmdGetCdoFields())
-	cmd.AddCommand(NewCmdGetContactFields())
-	cmd.AddCommand(NewCmdGetEmails())
-	cmd.AddCommand(NewCmdGetEmailGroups())
-	cmd.AddCommand(NewCmdGetForms())
+	cmd.AddCommand(NewCmdGetAccounts(f))
+	cmd.AddCommand(NewCmdGetAccountGroups(f))
+	cmd.AddCommand(NewCmdGetCampaigns(f))
+	cmd.AddCommand(NewCmdGetCdoFields(f))
+	cmd.AddCommand(NewCmdGetContactFields(f))
+	cmd.AddCommand(NewCmdGetContacts(f))
+	cmd.AddCommand(NewCmdGetEmails(f))
+	cmd.AddCommand(NewCmdGetEmailGroups(f))
+	cmd.AddCommand(NewCmdGetFieldSets())
+	cmd.AddCommand(NewCmdGetForms(f))
 
 	return cmd
 }
-
-func initClient() *bulk.BulkClient {
-	bauth := viper.GetStringMap("auth")
-	bulkURL := strings.Replace(viper.GetString("bulkUrl"), "{version}", apiVersion, 1)
-	username := fmt.Sprintf("%v\\%v", bauth["company"], bauth["username"])
-	password := bauth["password"]
-
-	tr := auth.BasicAuthTransport{Username: username, Password: password.(string)}
-	client := bulk.NewClient(bulkURL, tr.Client())
-
-	return client
-}
-
-func initRestClient() *rest.RestClient {
-	bauth := viper.GetStringMap("auth")
-	restURL := strings.Replace(viper.GetString("restUrl"), "{version}", apiVersion, 1)
-	username := fmt.Sprintf("%v\\%v", bauth["company"], bauth["username"])
-	password := bauth["password"]
-
-	tr := auth.BasicAuthTransport{Username: username, Password: password.(string)}
-	client := rest.NewClient(restURL, tr.Client())
-
-	return client
-}
@@ -1 +1,356 @@
+/*
+Copyright © 2019 elqx <ignotas.petrulis@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
 package get
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloqua-go/eloqua/rest"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/printers"
+	"github.com/elqx/eloquactl/pkg/restapi"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	getAccountsLong = templates.LongDesc(`
+		Get Eloqua accounts by id or name, or search them.
+
+		Without arguments, the accounts matching --filter are listed, a page at a time or
+		all of them with --all. The search is that of the REST API, e.g. name=Acme* or
+		country=Germany.
+
+		The CONTACTS column is empty unless --count-contacts is given. The contacts linked
+		to every account are then counted by searching the contacts by their accountId
+		field, one more request per account. The requests
+		are sent concurrently, within --max-concurrent-requests. At --depth=complete, the
+		json and ndj output formats show the values of all account fields by field name.`)
+
+	getAccountsExample = templates.Examples(`
+		# Get an account by id and another by name
+		eloquactl get accounts 42 "Acme Corporation"
+
+		# Get all the accounts of a country with the number of their contacts
+		eloquactl get accounts --filter='country=Germany' --all --count-contacts
+
+		# Get all the field values of an account
+		eloquactl get account 42 --depth=complete -o json`)
+)
+
+// contactCounters is the number of accounts whose contacts are counted at
+// once, the requests are limited by --max-concurrent-requests too.
+const contactCounters = 10
+
+type GetAccountsOptions struct {
+	Client     func() (*rest.RestClient, error)
+	BulkClient func() (*bulk.BulkClient, error)
+	Cache      func() (*cache.Cache, error)
+	ListFlags  *cmdutil.ListFlags
+
+	PrintFlags *cmdutil.PrintFlags
+
+	All           bool
+	CountContacts bool
+
+	// fieldNames maps account field ids to names, see setFields.
+	fieldNames map[string]string
+}
+
+func NewGetAccountsOptions(f *factory.Factory) *GetAccountsOptions {
+	return &GetAccountsOptions{
+		Client:     f.RestClient,
+		BulkClient: f.BulkClient,
+		Cache:      f.Cache,
+		ListFlags:  cmdutil.NewListFlags(),
+		PrintFlags: cmdutil.NewPrintFlags(),
+	}
+}
+
+func NewCmdGetAccounts(f *factory.Factory) *cobra.Command {
+	o := NewGetAccountsOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "accounts [ID|NAME]...",
+		Short:   "Get Eloqua accounts by id or name, or search them.",
+		Aliases: []string{"account"},
+		Long:    getAccountsLong,
+		Example: getAccountsExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(args); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(args)
+		},
+	}
+
+	o.ListFlags.AddFlags(cmd)
+	o.PrintFlags.AddFlags(cmd)
+
+	cmd.Flags().BoolVar(&o.All, "all", false, "Specifies whether all accounts matching the filter should be retrieved.")
+	cmd.Flags().BoolVar(&o.CountContacts, "count-contacts", o.CountContacts, "Specifies whether the contacts linked to the accounts should be counted. The CONTACTS column is empty otherwise.")
+
+	return cmd
+}
+
+func (o *GetAccountsOptions) Validate(args []string) error {
+	if err := o.PrintFlags.Validate(); err != nil {
+		return err
+	}
+
+	if err := o.ListFlags.Validate(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 && (o.All || *o.ListFlags.Search != "") {
+		return fmt.Errorf("--all and --filter can not be used with account ids or names")
+	}
+	return nil
+}
+
+func (o *GetAccountsOptions) Run(args []string) error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	w := printers.NewTabWriter(os.Stdout)
+
+	opts := &rest.GetOptions{
+		Count:   *o.ListFlags.Count,
+		Depth:   *o.ListFlags.Depth,
+		OrderBy: *o.ListFlags.OrderBy,
+		Page:    *o.ListFlags.Page,
+		Search:  *o.ListFlags.Search,
+	}
+
+	if len(args) > 0 {
+		return o.runGet(ctx, client, args, printer, w)
+	}
+
+	list := func(opts *rest.GetOptions) (*restapi.AccountList, error) {
+		return restapi.ListAccounts(ctx, client, opts)
+	}
+	return o.printAccounts(ctx, client, list, opts, printer, w)
+}
+
+// printAccounts prints the accounts listed a page at a time, all the pages
+// with --all. It is shared with the members of account groups.
+func (o *GetAccountsOptions) printAccounts(ctx context.Context, client *rest.RestClient, list func(*rest.GetOptions) (*restapi.AccountList, error), opts *rest.GetOptions, printer printers.ResourcePrinter, w *tabwriter.Writer) error {
+	if !o.All {
+		accounts, err := list(opts)
+		if err != nil {
+			return err
+		}
+
+		if err := o.complete(ctx, client, accounts.Elements); err != nil {
+			return err
+		}
+		printer.PrintResource(accounts.Elements, w)
+		w.Flush()
+		return nil
+	}
+
+	pageSize := *o.ListFlags.Count
+	totalResults := 99999999 // upper estimate for the number of accounts
+	for page := 1; (page-1)*pageSize <= totalResults; page++ {
+		opts.Page = page
+		accounts, err := list(opts)
+		if err != nil {
+			return err
+		}
+		totalResults = accounts.Total
+		if len(accounts.Elements) == 0 {
+			break
+		}
+
+		if err := o.complete(ctx, client, accounts.Elements); err != nil {
+			return err
+		}
+		printer.PrintResource(accounts.Elements, w)
+		w.Flush()
+	}
+	return nil
+}
+
+// runGet prints the accounts given by id or name. Accounts that are not
+// found are reported after the others are printed.
+func (o *GetAccountsOptions) runGet(ctx context.Context, client *rest.RestClient, args []string, printer printers.ResourcePrinter, w *tabwriter.Writer) error {
+	var accounts []restapi.Account
+	var missing []string
+	for _, arg := range args {
+		if contactIDRegex.MatchString(arg) {
+			a, err := restapi.GetAccount(ctx, client, arg, *o.ListFlags.Depth)
+			if err != nil && !cmdutil.IsNotFound(err) {
+				return err
+			}
+			if err != nil || a.ID == "" {
+				missing = append(missing, arg)
+				continue
+			}
+			accounts = append(accounts, *a)
+			continue
+		}
+
+		r, err := restapi.ListAccounts(ctx, client, &rest.GetOptions{
+			Count:  1000,
+			Depth:  *o.ListFlags.Depth,
+			Search: restapi.SearchTerm("name", arg),
+		})
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, a := range r.Elements {
+			if strings.EqualFold(a.Name, arg) {
+				accounts = append(accounts, a)
+				found = true
+			}
+		}
+		if !found {
+			missing = append(missing, arg)
+		}
+	}
+
+	if len(accounts) > 0 {
+		if err := o.complete(ctx, client, accounts); err != nil {
+			return err
+		}
+		printer.PrintResource(accounts, w)
+		w.Flush()
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("accounts not found: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// complete names the field values of the accounts and, unless disabled,
+// counts the contacts linked to them.
+func (o *GetAccountsOptions) complete(ctx context.Context, client *rest.RestClient, accounts []restapi.Account) error {
+	if err := o.setFields(ctx, accounts); err != nil {
+		return err
+	}
+
+	if !o.CountContacts {
+		return nil
+	}
+
+	counts := make([]int, len(accounts))
+	errs := make([]error, len(accounts))
+
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for n := 0; n < contactCounters; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				counts[i], errs[i] = restapi.CountAccountContacts(ctx, client, accounts[i].ID)
+			}
+		}()
+	}
+
+	for i := range accounts {
+		if ctx.Err() != nil {
+			break
+		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return &cmdutil.InterruptedError{}
+	}
+
+	for i := range accounts {
+		if errs[i] != nil {
+			return fmt.Errorf("failed to count the contacts of account %s: %w", accounts[i].ID, errs[i])
+		}
+		accounts[i].Contacts = &counts[i]
+	}
+	return nil
+}
+
+// setFields maps the field values of the accounts to the names of the
+// account fields, listed once and cached like the other metadata.
+func (o *GetAccountsOptions) setFields(ctx context.Context, accounts []restapi.Account) error {
+	if o.fieldNames == nil {
+		values := false
+		for _, a := range accounts {
+			if len(a.FieldValues) > 0 {
+				values = true
+				break
+			}
+		}
+		if !values {
+			return nil
+		}
+
+		client, err := o.BulkClient()
+		if err != nil {
+			return err
+		}
+
+		c, err := o.Cache()
+		if err != nil {
+			return err
+		}
+
+		md := &bulkapi.Metadata{Client: client, Cache: c}
+		fields, err := md.AccountFields(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list account fields: %w", err)
+		}
+
+		o.fieldNames = map[string]string{}
+		for _, f := range fields.Items {
+			o.fieldNames[path.Base(f.Uri)] = f.Name
+		}
+	}
+
+	for n := range accounts {
+		accounts[n].SetFields(o.fieldNames)
+	}
+	return nil
+}
@@ -1 +1,255 @@
+/*
+Copyright © 2019 elqx <ignotas.petrulis@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
 package get
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/elqx/eloqua-go/eloqua/rest"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/printers"
+	"github.com/elqx/eloquactl/pkg/restapi"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	getAccountGroupsLong = templates.LongDesc(`
+		Get Eloqua account groups by id or name, or search them.
+
+		Without arguments, the account groups matching --filter are listed, a page at a time
+		or all of them with --all.
+
+		With --members, the accounts in the given groups are listed instead, like
+		"get accounts" lists them: --filter, --all and --count-contacts then apply to
+		the accounts. The CONTACTS column is empty unless --count-contacts is given.`)
+
+	getAccountGroupsExample = templates.Examples(`
+		# List the account groups
+		eloquactl get account-groups
+
+		# Get an account group by name
+		eloquactl get account-group "Key Accounts" -o json
+
+		# List all the accounts in a group
+		eloquactl get account-groups 7 --members --all`)
+)
+
+type GetAccountGroupsOptions struct {
+	Client     func() (*rest.RestClient, error)
+	ListFlags  *cmdutil.ListFlags
+	PrintFlags *cmdutil.PrintFlags
+
+	All     bool
+	Members bool
+
+	// accounts lists the members of the groups, sharing the flags above.
+	accounts *GetAccountsOptions
+}
+
+func NewGetAccountGroupsOptions(f *factory.Factory) *GetAccountGroupsOptions {
+	accounts := NewGetAccountsOptions(f)
+	return &GetAccountGroupsOptions{
+		Client:     f.RestClient,
+		ListFlags:  accounts.ListFlags,
+		PrintFlags: accounts.PrintFlags,
+		accounts:   accounts,
+	}
+}
+
+func NewCmdGetAccountGroups(f *factory.Factory) *cobra.Command {
+	o := NewGetAccountGroupsOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "account-groups [ID|NAME]...",
+		Short:   "Get Eloqua account groups by id or name, or the accounts in them.",
+		Aliases: []string{"account-group"},
+		Long:    getAccountGroupsLong,
+		Example: getAccountGroupsExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(args); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(args)
+		},
+	}
+
+	o.ListFlags.AddFlags(cmd)
+	o.PrintFlags.AddFlags(cmd)
+
+	cmd.Flags().BoolVar(&o.All, "all", false, "Specifies whether all account groups, or all members with --members, should be retrieved.")
+	cmd.Flags().BoolVar(&o.Members, "members", false, "List the accounts in the given account groups.")
+	cmd.Flags().BoolVar(&o.accounts.CountContacts, "count-contacts", o.accounts.CountContacts, "Specifies whether the contacts linked to the members should be counted. The CONTACTS column is empty otherwise.")
+
+	return cmd
+}
+
+func (o *GetAccountGroupsOptions) Validate(args []string) error {
+	if err := o.PrintFlags.Validate(); err != nil {
+		return err
+	}
+
+	if err := o.ListFlags.Validate(); err != nil {
+		return err
+	}
+
+	if o.Members {
+		if len(args) == 0 {
+			return fmt.Errorf("--members requires the ids or names of account groups")
+		}
+		return nil
+	}
+
+	if len(args) > 0 && (o.All || *o.ListFlags.Search != "") {
+		return fmt.Errorf("--all and --filter can not be used with account group ids or names, unless --members is given")
+	}
+	return nil
+}
+
+func (o *GetAccountGroupsOptions) Run(args []string) error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	w := printers.NewTabWriter(os.Stdout)
+
+	opts := &rest.GetOptions{
+		Count:   *o.ListFlags.Count,
+		Depth:   *o.ListFlags.Depth,
+		OrderBy: *o.ListFlags.OrderBy,
+		Page:    *o.ListFlags.Page,
+		Search:  *o.ListFlags.Search,
+	}
+
+	if len(args) > 0 {
+		groups, err := o.getGroups(ctx, client, args)
+		if err != nil {
+			return err
+		}
+
+		if !o.Members {
+			printer.PrintResource(groups, w)
+			w.Flush()
+			return nil
+		}
+
+		o.accounts.All = o.All
+		for _, g := range groups {
+			id := g.ID
+			list := func(opts *rest.GetOptions) (*restapi.AccountList, error) {
+				return restapi.ListAccountGroupMembers(ctx, client, id, opts)
+			}
+			if err := o.accounts.printAccounts(ctx, client, list, opts, printer, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if o.All {
+		pageSize := *o.ListFlags.Count
+		totalResults := 99999999 // upper estimate for the number of account groups
+		for page := 1; (page-1)*pageSize <= totalResults; page++ {
+			opts.Page = page
+			groups, err := restapi.ListAccountGroups(ctx, client, opts)
+			if err != nil {
+				return err
+			}
+			totalResults = groups.Total
+			if len(groups.Elements) == 0 {
+				break
+			}
+
+			printer.PrintResource(groups.Elements, w)
+			w.Flush()
+		}
+	} else {
+		groups, err := restapi.ListAccountGroups(ctx, client, opts)
+		if err != nil {
+			return err
+		}
+
+		printer.PrintResource(groups.Elements, w)
+		w.Flush()
+	}
+
+	return nil
+}
+
+// getGroups returns the account groups given by id or name. Unlike the
+// accounts, a group that is not found fails before anything is printed.
+func (o *GetAccountGroupsOptions) getGroups(ctx context.Context, client *rest.RestClient, args []string) ([]restapi.AccountGroup, error) {
+	var groups []restapi.AccountGroup
+	var missing []string
+	for _, arg := range args {
+		if contactIDRegex.MatchString(arg) {
+			g, err := restapi.GetAccountGroup(ctx, client, arg, *o.ListFlags.Depth)
+			if err != nil && !cmdutil.IsNotFound(err) {
+				return nil, err
+			}
+			if err != nil || g.ID == "" {
+				missing = append(missing, arg)
+				continue
+			}
+			groups = append(groups, *g)
+			continue
+		}
+
+		r, err := restapi.ListAccountGroups(ctx, client, &rest.GetOptions{
+			Count:  1000,
+			Depth:  *o.ListFlags.Depth,
+			Search: restapi.SearchTerm("name", arg),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		found := false
+		for _, g := range r.Elements {
+			if strings.EqualFold(g.Name, arg) {
+				groups = append(groups, g)
+				found = true
+			}
+		}
+		if !found {
+			missing = append(missing, arg)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("account groups not found: %s", strings.Join(missing, ", "))
+	}
+	return groups, nil
+}
@@ -22,14 +22,15 @@ THE SOFTWARE.
 package get
 
 import (
-	"context"
 	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/factory"
 	"github.com/elqx/eloquactl/pkg/printers"
 	cmdutil "github.com/elqx/eloquactl/pkg/util"
 	"github.com/elqx/eloquactl/pkg/util/templates"
 	"github.com/spf13/cobra"
 	"os"
-	"strconv"
 )
 
 var (
@@ -47,22 +48,24 @@ var (
 )
 
 type GetCdoFieldsOptions struct {
-	Client     func() *bulk.BulkClient
+	Client     func() (*bulk.BulkClient, error)
+	Cache      func() (*cache.Cache, error)
 	PrintFlags *cmdutil.PrintFlags
 
 	// Command specific options
 	Bulk bool // if True, then feelds are retrieved via Bulk API, not Rest Standard API
 }
 
-func NewGetCdoFieldsOptions() *GetCdoFieldsOptions {
+func NewGetCdoFieldsOptions(f *factory.Factory) *GetCdoFieldsOptions {
 	return &GetCdoFieldsOptions{
-		Client:     initClient,
+		Client:     f.BulkClient,
+		Cache:      f.Cache,
 		PrintFlags: cmdutil.NewPrintFlags(),
 	}
 }
 
-func NewCmdGetCdoFields() *cobra.Command {
-	o := NewGetCdoFieldsOptions()
+func NewCmdGetCdoFields(f *factory.Factory) *cobra.Command {
+	o := NewGetCdoFieldsOptions(f)
 
 	cmd := &cobra.Command{
 		Use:     "cdo-fields <NAME>",
@@ -70,14 +73,17 @@ func NewCmdGetCdoFields() *cobra.Command {
 		Aliases: []string{"cdo-field"},
 		Long:    exportCdoFieldsLong,
 		Example: exportCdoFieldsExample,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
-				cmdutil.Er("To get CDO fields you need to provide CDO name or ID for the command.")
+				return cmdutil.ValidationErrorf("to get CDO fields you need to provide CDO name or ID for the command")
 			}
 
 			//		o.Complete(cmd)
-			o.Validate(cmd)
-			o.Run(cmd, args)
+			if err := o.Validate(cmd); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(cmd, args)
 		},
 	}
 
@@ -99,33 +105,25 @@ func (p *GetCdoFieldsOptions) Validate(cmd *cobra.Command) error {
 }
 
 func (p *GetCdoFieldsOptions) Run(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-	client := p.Client()
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := p.Client()
+	if err != nil {
+		return err
+	}
 
-	var parentId int
-	// check if args[0] is numeric or string
-	parentId, err := strconv.Atoi(args[0])
+	c, err := p.Cache()
 	if err != nil {
-		// name is given, find id
-		// TODO: check cache first)
-		cdos, err := client.Cdos.List(ctx)
-		if err != nil {
-			cmdutil.Er("Failed listing CDOs")
-		}
-
-		for _, cdo := range cdos.Items {
-			if cdo.Name == args[0] {
-				// skipping /customObjects/ prefix
-				parentId, err = strconv.Atoi(cdo.Uri[15:])
-				if err != nil {
-					cmdutil.Er("Error extracting custom object id")
-				}
-				break
-			}
-		}
+		return err
+	}
+
+	md := &bulkapi.Metadata{Client: client, Cache: c}
+	parentId, err := md.CdoID(ctx, args[0])
+	if err != nil {
+		return err
 	}
 
-	fields, err := client.Cdos.ListFields(ctx, parentId)
+	fields, err := md.CdoFields(ctx, parentId)
 	if err != nil {
 		return err
 	}
@@ -24,8 +24,10 @@ package get
 import (
 	"os"
 	//"fmt"
-	"context"
 	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/factory"
 	"github.com/elqx/eloquactl/pkg/printers"
 	cmdutil "github.com/elqx/eloquactl/pkg/util"
 	"github.com/elqx/eloquactl/pkg/util/templates"
@@ -44,22 +46,24 @@ var (
 )
 
 type GetContactFieldsOptions struct {
-	Client     func() *bulk.BulkClient
+	Client     func() (*bulk.BulkClient, error)
+	Cache      func() (*cache.Cache, error)
 	PrintFlags *cmdutil.PrintFlags
 
 	// Command specific options
 	Bulk bool // if True, then feelds are retrieved via Bulk API, not Rest Standard API
 }
 
-func NewGetContactFieldsOptions() *GetContactFieldsOptions {
+func NewGetContactFieldsOptions(f *factory.Factory) *GetContactFieldsOptions {
 	return &GetContactFieldsOptions{
-		Client:     initClient,
+		Client:     f.BulkClient,
+		Cache:      f.Cache,
 		PrintFlags: cmdutil.NewPrintFlags(),
 	}
 }
 
-func NewCmdGetContactFields() *cobra.Command {
-	o := NewGetContactFieldsOptions()
+func NewCmdGetContactFields(f *factory.Factory) *cobra.Command {
+	o := NewGetContactFieldsOptions(f)
 
 	cmd := &cobra.Command{
 		Use:     "contact-fields",
@@ -67,9 +71,12 @@ func NewCmdGetContactFields() *cobra.Command {
 		Aliases: []string{"cdo-field"},
 		Long:    getContactFieldsLong,
 		Example: getContactFieldsExample,
-		Run: func(cmd *cobra.Command, args []string) {
-			o.Validate(cmd)
-			o.Run(cmd, args)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(cmd); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(cmd, args)
 		},
 	}
 
@@ -91,10 +98,20 @@ func (p *GetContactFieldsOptions) Validate(cmd *cobra.Command) error {
 }
 
 func (p *GetContactFieldsOptions) Run(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-	client := p.Client()
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := p.Client()
+	if err != nil {
+		return err
+	}
+
+	c, err := p.Cache()
+	if err != nil {
+		return err
+	}
 
-	fields, err := client.Contacts.GetFields(ctx)
+	md := &bulkapi.Metadata{Client: client, Cache: c}
+	fields, err := md.ContactFields(ctx)
 	if err != nil {
 		return err
 	}
@@ -1 +1,298 @@
+/*
+Copyright © 2019 elqx <ignotas.petrulis@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
 package get
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloqua-go/eloqua/rest"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/printers"
+	"github.com/elqx/eloquactl/pkg/restapi"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	getContactsLong = templates.LongDesc(`
+		Get Eloqua contacts by id or email address, or search them.
+
+		Without arguments, the contacts matching --filter are listed, a page at a time or
+		all of them with --all. The search is that of the REST API, e.g. emailAddress=*@acme.com
+		or lastName=Smith.
+
+		At --depth=complete, the values of all contact fields are returned. The json and ndj
+		output formats then show them by the name of the field, custom fields included.`)
+
+	getContactsExample = templates.Examples(`
+		# Get a contact by id and another by email address
+		eloquactl get contacts 12345 jane.doe@acme.com
+
+		# Get all the contacts of a domain
+		eloquactl get contacts --filter='emailAddress=*@acme.com' --all
+
+		# Get all the field values of a contact
+		eloquactl get contact jane.doe@acme.com --depth=complete -o json`)
+)
+
+var contactIDRegex = regexp.MustCompile(`^[0-9]+$`)
+
+type GetContactsOptions struct {
+	Client     func() (*rest.RestClient, error)
+	BulkClient func() (*bulk.BulkClient, error)
+	Cache      func() (*cache.Cache, error)
+	ListFlags  *cmdutil.ListFlags
+
+	PrintFlags *cmdutil.PrintFlags
+
+	All bool
+
+	// fieldNames maps contact field ids to names, see setFields.
+	fieldNames map[string]string
+}
+
+func NewGetContactsOptions(f *factory.Factory) *GetContactsOptions {
+	return &GetContactsOptions{
+		Client:     f.RestClient,
+		BulkClient: f.BulkClient,
+		Cache:      f.Cache,
+		ListFlags:  cmdutil.NewListFlags(),
+		PrintFlags: cmdutil.NewPrintFlags(),
+	}
+}
+
+func NewCmdGetContacts(f *factory.Factory) *cobra.Command {
+	o := NewGetContactsOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "contacts [ID|EMAIL]...",
+		Short:   "Get Eloqua contacts by id or email address, or search them.",
+		Aliases: []string{"contact"},
+		Long:    getContactsLong,
+		Example: getContactsExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(args); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(args)
+		},
+	}
+
+	o.ListFlags.AddFlags(cmd)
+	o.PrintFlags.AddFlags(cmd)
+
+	cmd.Flags().BoolVar(&o.All, "all", false, "Specifies whether all contacts matching the filter should be retrieved.")
+
+	return cmd
+}
+
+func (o *GetContactsOptions) Validate(args []string) error {
+	if err := o.PrintFlags.Validate(); err != nil {
+		return err
+	}
+
+	if err := o.ListFlags.Validate(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 && (o.All || *o.ListFlags.Search != "") {
+		return fmt.Errorf("--all and --filter can not be used with contact ids or email addresses")
+	}
+
+	for _, arg := range args {
+		if !contactIDRegex.MatchString(arg) && !strings.Contains(arg, "@") {
+			return fmt.Errorf("%q is neither a contact id nor an email address", arg)
+		}
+	}
+	return nil
+}
+
+func (o *GetContactsOptions) Run(args []string) error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	w := printers.NewTabWriter(os.Stdout)
+
+	opts := &rest.GetOptions{
+		Count:   *o.ListFlags.Count,
+		Depth:   *o.ListFlags.Depth,
+		OrderBy: *o.ListFlags.OrderBy,
+		Page:    *o.ListFlags.Page,
+		Search:  *o.ListFlags.Search,
+	}
+
+	if len(args) > 0 {
+		return o.runGet(ctx, client, args, printer, w)
+	}
+
+	if o.All {
+		pageSize := *o.ListFlags.Count
+		totalResults := 99999999 // upper estimate for the number of contacts
+		for page := 1; (page-1)*pageSize <= totalResults; page++ {
+			opts.Page = page
+			contacts, err := restapi.ListContacts(ctx, client, opts)
+			if err != nil {
+				return err
+			}
+			totalResults = contacts.Total
+			if len(contacts.Elements) == 0 {
+				break
+			}
+
+			if err := o.setFields(ctx, contacts.Elements); err != nil {
+				return err
+			}
+			printer.PrintResource(contacts.Elements, w)
+			w.Flush()
+		}
+	} else {
+		contacts, err := restapi.ListContacts(ctx, client, opts)
+		if err != nil {
+			return err
+		}
+
+		if err := o.setFields(ctx, contacts.Elements); err != nil {
+			return err
+		}
+		printer.PrintResource(contacts.Elements, w)
+		w.Flush()
+	}
+
+	return nil
+}
+
+// runGet prints the contacts given by id or email address. Contacts that
+// are not found are reported after the others are printed.
+func (o *GetContactsOptions) runGet(ctx context.Context, client *rest.RestClient, args []string, printer printers.ResourcePrinter, w *tabwriter.Writer) error {
+	var contacts []restapi.Contact
+	var missing []string
+	for _, arg := range args {
+		if contactIDRegex.MatchString(arg) {
+			c, err := restapi.GetContact(ctx, client, arg, *o.ListFlags.Depth)
+			if err != nil && !cmdutil.IsNotFound(err) {
+				return err
+			}
+			if err != nil || c.ID == "" {
+				missing = append(missing, arg)
+				continue
+			}
+			contacts = append(contacts, *c)
+			continue
+		}
+
+		r, err := restapi.ListContacts(ctx, client, &rest.GetOptions{
+			Count:  1000,
+			Depth:  *o.ListFlags.Depth,
+			Search: restapi.SearchTerm("emailAddress", arg),
+		})
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, c := range r.Elements {
+			if strings.EqualFold(c.EmailAddress, arg) {
+				contacts = append(contacts, c)
+				found = true
+			}
+		}
+		if !found {
+			missing = append(missing, arg)
+		}
+	}
+
+	if len(contacts) > 0 {
+		if err := o.setFields(ctx, contacts); err != nil {
+			return err
+		}
+		printer.PrintResource(contacts, w)
+		w.Flush()
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("contacts not found: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// setFields maps the field values of the contacts to the names of the
+// contact fields, listed once and cached like the other metadata.
+func (o *GetContactsOptions) setFields(ctx context.Context, contacts []restapi.Contact) error {
+	if o.fieldNames == nil {
+		values := false
+		for _, c := range contacts {
+			if len(c.FieldValues) > 0 {
+				values = true
+				break
+			}
+		}
+		if !values {
+			return nil
+		}
+
+		client, err := o.BulkClient()
+		if err != nil {
+			return err
+		}
+
+		c, err := o.Cache()
+		if err != nil {
+			return err
+		}
+
+		md := &bulkapi.Metadata{Client: client, Cache: c}
+		fields, err := md.ContactFields(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list contact fields: %w", err)
+		}
+
+		o.fieldNames = map[string]string{}
+		for _, f := range fields.Items {
+			o.fieldNames[path.Base(f.Uri)] = f.Name
+		}
+	}
+
+	for n := range contacts {
+		contacts[n].SetFields(o.fieldNames)
+	}
+	return nil
+}
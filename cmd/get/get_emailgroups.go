@@ -23,7 +23,7 @@ THE SOFTWARE.
 package get
 
 import (
-	"context"
+	"github.com/elqx/eloquactl/pkg/factory"
 	"github.com/elqx/eloquactl/pkg/printers"
 	cmdutil "github.com/elqx/eloquactl/pkg/util"
 	"github.com/elqx/eloquactl/pkg/util/templates"
@@ -45,7 +45,7 @@ var (
 )
 
 type GetEmailGroupsOptions struct {
-	Client    func() *rest.RestClient
+	Client    func() (*rest.RestClient, error)
 	ListFlags *cmdutil.ListFlags
 
 	PrintFlags *cmdutil.PrintFlags
@@ -53,16 +53,16 @@ type GetEmailGroupsOptions struct {
 	All bool
 }
 
-func NewGetEmailGroupsOptions() *GetEmailGroupsOptions {
+func NewGetEmailGroupsOptions(f *factory.Factory) *GetEmailGroupsOptions {
 	return &GetEmailGroupsOptions{
-		Client:     initRestClient,
+		Client:     f.RestClient,
 		ListFlags:  cmdutil.NewListFlags(),
 		PrintFlags: cmdutil.NewPrintFlags(),
 	}
 }
 
-func NewCmdGetEmailGroups() *cobra.Command {
-	o := NewGetEmailGroupsOptions()
+func NewCmdGetEmailGroups(f *factory.Factory) *cobra.Command {
+	o := NewGetEmailGroupsOptions(f)
 
 	cmd := &cobra.Command{
 		Use:     "emailgroups <NAME>",
@@ -70,9 +70,12 @@ func NewCmdGetEmailGroups() *cobra.Command {
 		Aliases: []string{"emailgroup"},
 		Long:    getEmailGroupsLong,
 		Example: getEmailGroupsExample,
-		Run: func(cmd *cobra.Command, args []string) {
-			o.Validate(cmd)
-			o.Run(cmd, args)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(cmd); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(cmd, args)
 		},
 	}
 
@@ -96,8 +99,12 @@ func (p *GetEmailGroupsOptions) Validate(cmd *cobra.Command) error {
 }
 
 func (p *GetEmailGroupsOptions) Run(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-	client := p.Client()
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := p.Client()
+	if err != nil {
+		return err
+	}
 
 	printer, err := p.PrintFlags.ToPrinter()
 	if err != nil {
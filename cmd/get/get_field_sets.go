@@ -0,0 +1,96 @@
+package get
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/elqx/eloquactl/pkg/fieldsets"
+	"github.com/elqx/eloquactl/pkg/printers"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	getFieldSetsLong = templates.LongDesc(`
+		List the field sets that can be exported with --field-set.
+
+		Built-in field sets exist for every activity type. More field sets can be
+		defined in the fieldSets section of the config file, a field set there
+		replaces the built-in one with the same name.`)
+
+	getFieldSetsExample = templates.Examples(`
+		# List all field sets
+		eloquactl get field-sets
+
+		# List the field sets of activities
+		eloquactl get field-sets --entity=activities
+
+		# Print the fields of a field set
+		eloquactl get field-sets EmailOpen -o json`)
+)
+
+type GetFieldSetsOptions struct {
+	PrintFlags *cmdutil.PrintFlags
+
+	Entity string
+}
+
+func NewCmdGetFieldSets() *cobra.Command {
+	o := &GetFieldSetsOptions{
+		PrintFlags: cmdutil.NewPrintFlags(),
+	}
+
+	cmd := &cobra.Command{
+		Use:     "field-sets [NAME]",
+		Short:   "List the field sets that can be exported with --field-set",
+		Aliases: []string{"field-set"},
+		Long:    getFieldSetsLong,
+		Example: getFieldSetsExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return cmdutil.ValidationErrorf("at most one field set name can be given")
+			}
+
+			if err := o.PrintFlags.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(args)
+		},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+	cmd.Flags().StringVar(&o.Entity, "entity", "", "Entity type of the field sets. One of: contacts|accounts|activities|cdos|scores.")
+
+	return cmd
+}
+
+func (o *GetFieldSetsOptions) Run(args []string) error {
+	sets, err := fieldsets.List(o.Entity)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		var named []fieldsets.FieldSet
+		for _, s := range sets {
+			if s.Name == args[0] {
+				named = append(named, s)
+			}
+		}
+		if len(named) == 0 {
+			return fmt.Errorf("field set %q not found", args[0])
+		}
+		sets = named
+	}
+
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	w := printers.NewTabWriter(os.Stdout)
+	defer w.Flush()
+
+	return printer.PrintResource(sets, w)
+}
@@ -23,7 +23,7 @@ THE SOFTWARE.
 package get
 
 import (
-	"context"
+	"github.com/elqx/eloquactl/pkg/factory"
 	"github.com/elqx/eloquactl/pkg/printers"
 	cmdutil "github.com/elqx/eloquactl/pkg/util"
 	"github.com/elqx/eloquactl/pkg/util/templates"
@@ -45,7 +45,7 @@ var (
 )
 
 type GetFormsOptions struct {
-	Client    func() *rest.RestClient
+	Client    func() (*rest.RestClient, error)
 	ListFlags *cmdutil.ListFlags
 
 	PrintFlags *cmdutil.PrintFlags
@@ -53,16 +53,16 @@ type GetFormsOptions struct {
 	All bool
 }
 
-func NewGetFormsOptions() *GetFormsOptions {
+func NewGetFormsOptions(f *factory.Factory) *GetFormsOptions {
 	return &GetFormsOptions{
-		Client:     initRestClient,
+		Client:     f.RestClient,
 		ListFlags:  cmdutil.NewListFlags(),
 		PrintFlags: cmdutil.NewPrintFlags(),
 	}
 }
 
-func NewCmdGetForms() *cobra.Command {
-	o := NewGetFormsOptions()
+func NewCmdGetForms(f *factory.Factory) *cobra.Command {
+	o := NewGetFormsOptions(f)
 
 	cmd := &cobra.Command{
 		Use:     "forms <NAME>",
@@ -70,14 +70,17 @@ func NewCmdGetForms() *cobra.Command {
 		Aliases: []string{"form"},
 		Long:    getFormsLong,
 		Example: getFormsExample,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			//if len(args) < 1 {
-			//	cmdutil.Er("To get forms you need to provide its name or ID for the command.")
+			//	return cmdutil.ValidationErrorf("to get forms you need to provide its name or ID for the command")
 			//}
 
 			//		o.Complete(cmd)
-			o.Validate(cmd)
-			o.Run(cmd, args)
+			if err := o.Validate(cmd); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(cmd, args)
 		},
 	}
 
@@ -101,8 +104,12 @@ func (p *GetFormsOptions) Validate(cmd *cobra.Command) error {
 }
 
 func (p *GetFormsOptions) Run(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-	client := p.Client()
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := p.Client()
+	if err != nil {
+		return err
+	}
 
 	printer, err := p.PrintFlags.ToPrinter()
 	if err != nil {
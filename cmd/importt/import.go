@@ -1,24 +1,26 @@
 package importt
 
 import (
-	"fmt"
-
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
 	"github.com/spf13/cobra"
 )
 
 type ImportOptions struct {
 }
 
-func NewCmdImport() *cobra.Command {
+func NewCmdImport(f *factory.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "import",
 		Short: "Imports data into Eloqua",
 		Long:  "Imports data into Eloqua",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("executing import...")
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdutil.ValidationErrorf("you must specify the type of resource to import. See 'eloquactl import -h' for help and examples")
 		},
 	}
 
+	cmd.AddCommand(NewCmdImportContacts(f))
+
 	return cmd
 }
 
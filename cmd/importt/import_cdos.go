@@ -15,7 +15,8 @@ func NewCmdImportCdosCommand() {
 		Short:   "",
 		Long:    "",
 		Example: "",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nil
 		},
 	}
 
@@ -1,94 +1,163 @@
 package importt
 
 import (
-	//"context"
+	"errors"
+	"fmt"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/factory"
 	cmdutil "github.com/elqx/eloquactl/pkg/util"
 	"github.com/elqx/eloquactl/pkg/util/templates"
 	"github.com/spf13/cobra"
-	//"github.com/elqx/eloqua-go/eloqua/bulk"
+)
+
+const (
+	contactImportsPath = "/contacts/imports"
 )
 
 var (
 	importContactsLong = templates.LongDesc(`
 		Import contacts to Eloqua from a file or stdin.
-		
-		JSON and CSV file formats are supported`)
+
+		JSON and CSV file formats are supported. Columns are mapped to the contact fields
+		with the same name or internal name, unless --fields is given. Contacts are matched
+		by the email address column, unless --identifier is given.
+
+		The import definition is deleted when the command is interrupted, unless
+		--keep-definition is given. With --dry-run the import definition is printed
+		without creating it.`)
 
 	importContactsExample = templates.Examples(`
 		# Import contacts into Eloqua from a file
-		eloquactl import contacts -f=contacts.csv`)
+		eloquactl import contacts -f=contacts.csv
+
+		# Print the import definition without creating it
+		eloquactl import contacts -f=contacts.csv --dry-run`)
 )
 
 type ImportContactsOptions struct {
+	Client        func() (*bulk.BulkClient, error)
+	Cache         func() (*cache.Cache, error)
+	DryRun        *bool
 	ImportFlags   *cmdutil.ImportFlags
 	FileNameFlags *cmdutil.FileNameFlags
+	PrintFlags    *cmdutil.PrintFlags
 }
 
-func NewImportContactsOptions() *ImportContactsOptions {
+func NewImportContactsOptions(f *factory.Factory) *ImportContactsOptions {
 	return &ImportContactsOptions{
+		Client:        f.BulkClient,
+		Cache:         f.Cache,
+		DryRun:        &f.DryRun,
 		ImportFlags:   cmdutil.NewImportFlags(),
 		FileNameFlags: cmdutil.NewFileNameFlags(),
+		PrintFlags:    cmdutil.NewPrintFlags(),
 	}
 }
 
-func NewCmdImportContacts() *cobra.Command {
-	o := NewImportContactsOptions()
+func NewCmdImportContacts(f *factory.Factory) *cobra.Command {
+	o := NewImportContactsOptions(f)
 	cmd := &cobra.Command{
 		Use:     "contacts",
 		Aliases: []string{"contact"},
-		Short:   "Export Eloqua contacts to a file or stdout",
+		Short:   "Import contacts to Eloqua from a file or stdin",
 		Long:    importContactsLong,
 		Example: importContactsExample,
-		Run: func(cmd *cobra.Command, args []string) {
-			o.Validate()
-			o.Run(cmd, args)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(cmd, args)
 		},
 	}
 
 	// Add shared falgs
 	o.ImportFlags.AddFlags(cmd)
 	o.FileNameFlags.AddFlags(cmd)
+	o.PrintFlags.AddFlags(cmd)
 
 	return cmd
 }
 
 func (o *ImportContactsOptions) Validate() error {
-	//var e Errors
 	if err := o.ImportFlags.Validate(); err != nil {
-		//e = aggregateError(err)
 		return err
 	}
 
 	if err := o.FileNameFlags.Validate(); err != nil {
-		//e = aggregateError(err)
 		return err
 	}
 
-	return nil
+	if len(*o.FileNameFlags.FileNames) == 0 {
+		return errors.New("you must specify the contacts to import with -f")
+	}
+
+	return o.PrintFlags.Validate()
 }
 
-func (o *ImportContactsOptions) Run(cmd *cobra.Command, args []string) {
-	/*
-		ctx := context.Background()
-		i := &bulk.Import{
-			AutoDeleteDuration: o.ImportFlags.AutoDeleteDuration,
-			DataRetentionDuration: o.ImportFlags.DataRetentionDuration,
-			Fields: o.ImportFlags.Fields, //fix
-			IdentifierFieldName: o.ImportFlags.Identifier.FieldName,
-			IsSyncTriggeredOnImport: o.ImportFlags.IsSyncTriggeredOnImport,
-			IsUpdatingMultipleMatchedRecords: o.ImportFlags.IsUpdatingMultipleMatchedRecords,
-			Name: o.ImportFlags.Name,
-			NullIdentifierFieldName: o.ImportFlags.NullIdentifierName,
-			SyncActions: o.ImportFlags.SyncActions, //fix
-			UpdateRule: o.ImportFlags.UpdateRule,
-		}
-
-		i, err = client.Contacts.CreateImport(ctx, &i)
-		if err != nil {
-			return err
-		}
-
-		// read contacts from file
-		client.Contacts.Upload()
-	*/
+func (o *ImportContactsOptions) Run(cmd *cobra.Command, args []string) error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+
+	files, err := o.FileNameFlags.Files(".csv", ".json", ".ndj")
+	if err != nil {
+		return err
+	}
+
+	columns, records, err := cmdutil.ReadRecordFiles(files)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return cmdutil.ValidationErrorf("no contacts to import")
+	}
+
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	c, err := o.Cache()
+	if err != nil {
+		return err
+	}
+
+	md := &bulkapi.Metadata{Client: client, Cache: c}
+	fields, identifier, err := cmdutil.ContactImportFields(ctx, md, columns, o.ImportFlags)
+	if err != nil {
+		return err
+	}
+
+	name := *o.ImportFlags.Name
+	if name == "" {
+		name = "eloquactl import contacts"
+	}
+
+	i := &bulkapi.Import{
+		Name:                             name,
+		Fields:                           fields,
+		IdentifierFieldName:              identifier,
+		IsUpdatingMultipleMatchedRecords: *o.ImportFlags.IsUpdatingMultipleMatchedRecords,
+		UpdateRule:                       *o.ImportFlags.UpdateRule,
+		AutoDeleteDuration:               *o.ImportFlags.StagingFlags.AutoDeleteDuration,
+		DataRetentionDuration:            *o.ImportFlags.StagingFlags.DataRetentionDuration,
+	}
+
+	if *o.DryRun {
+		return cmdutil.PrintDryRun("POST", contactImportsPath, i)
+	}
+
+	// the definition is deleted by RunImport when the command is interrupted
+	results, err := cmdutil.RunImport(ctx, client, contactImportsPath, i, records, o.ImportFlags.SyncFlags, *o.ImportFlags.KeepDefinition)
+	if err != nil {
+		return err
+	}
+
+	return cmdutil.PrintImportResults(results, o.PrintFlags, func(imported, total int) string {
+		return fmt.Sprintf("Imported %d of %d contacts", imported, total)
+	})
 }
@@ -0,0 +1,316 @@
+package login
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/config"
+	"github.com/elqx/eloquactl/pkg/credentials"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/oauth"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultLoginURL = "https://login.eloqua.com"
+)
+
+var (
+	loginLong = templates.LongDesc(`
+		Log in to an Eloqua instance and save it as a context in the config file.
+
+		The command asks for the company, user name and password (or runs the OAuth2 flow),
+		calls Eloqua's login id endpoint to discover the pod specific base URLs and writes a
+		ready to use context. The context is named after the --context flag or the company.
+
+		The secrets are saved to the keyring when secret-tool is installed, otherwise in
+		plain text to the config file, unless --credential-source is given.`)
+
+	loginExample = templates.Examples(`
+		# Log in with basic auth, prompting for company, user name and password
+		eloquactl login
+
+		# Log in to the sandbox instance and name the context 'sandbox'
+		eloquactl login --company=AcmeSandbox --user=jdoe --context=sandbox
+
+		# Log in with OAuth2 authorization code grant
+		eloquactl login --oauth --client-id=ID --client-secret=SECRET`)
+)
+
+type LoginOptions struct {
+	Factory *factory.Factory
+
+	Company  string
+	User     string
+	Password string
+
+	// OAuth2 options
+	OAuth        bool
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Grant        string
+
+	// Where the secrets are saved, see 'eloquactl config set-credentials -h'.
+	CredentialSource string
+	CredentialPath   string
+
+	// LoginURL is the URL of the Eloqua login service, it serves the
+	// discovery (id) endpoint and the OAuth2 endpoints.
+	LoginURL string
+}
+
+func NewCmdLogin(f *factory.Factory) *cobra.Command {
+	o := &LoginOptions{Factory: f}
+
+	cmd := &cobra.Command{
+		Use:     "login",
+		Short:   "Log in to Eloqua and save the instance as a context",
+		Long:    loginLong,
+		Example: loginExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(); err != nil {
+				return err
+			}
+
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Company, "company", "", "Eloqua company (site) name.")
+	cmd.Flags().StringVar(&o.User, "user", "", "Eloqua user name.")
+	cmd.Flags().StringVar(&o.Password, "password", "", "Password of the user. Prompted for if not given.")
+	cmd.Flags().BoolVar(&o.OAuth, "oauth", false, "Authenticate with OAuth2 instead of basic auth.")
+	cmd.Flags().StringVar(&o.ClientID, "client-id", "", "OAuth2 client id of the App Cloud app.")
+	cmd.Flags().StringVar(&o.ClientSecret, "client-secret", "", "OAuth2 client secret of the App Cloud app.")
+	cmd.Flags().StringVar(&o.RedirectURL, "redirect-url", "", "OAuth2 redirect URL, the local callback listener is started on it.")
+	cmd.Flags().StringVar(&o.Grant, "grant", config.GrantAuthorizationCode, "OAuth2 grant type. One of: authorization_code|password.")
+	cmd.Flags().StringVar(&o.CredentialSource, "credential-source", "", "Where the secrets are saved. One of: inline|keyring|file. Defaults to keyring when secret-tool is installed, inline otherwise.")
+	cmd.Flags().StringVar(&o.CredentialPath, "credential-path", "", "Path of the encrypted file, used by the file credential source.")
+	cmd.Flags().StringVar(&o.LoginURL, "login-url", defaultLoginURL, "URL of the Eloqua login service used for base URL discovery.")
+
+	return cmd
+}
+
+// Complete prompts for the options that were not given as flags.
+func (o *LoginOptions) Complete() error {
+	var err error
+
+	if o.Company == "" {
+		if o.Company, err = cmdutil.Prompt("Company", ""); err != nil {
+			return err
+		}
+	}
+
+	if o.OAuth && o.ClientID == "" {
+		return cmdutil.ValidationErrorf("--oauth requires --client-id")
+	}
+
+	if o.CredentialSource == "" {
+		o.CredentialSource = config.SourceInline
+		if credentials.KeyringAvailable() {
+			o.CredentialSource = config.SourceKeyring
+		}
+	}
+
+	switch o.CredentialSource {
+	case config.SourceInline, config.SourceKeyring:
+	case config.SourceFile:
+		if o.CredentialPath == "" {
+			return cmdutil.ValidationErrorf("--credential-source=file requires --credential-path")
+		}
+	default:
+		return cmdutil.ValidationErrorf("secrets can not be saved to the %q credential source", o.CredentialSource)
+	}
+
+	// the authorization code grant does not need user name nor password
+	if o.OAuth && o.Grant != config.GrantPassword {
+		return nil
+	}
+
+	if o.User == "" {
+		if o.User, err = cmdutil.Prompt("User name", ""); err != nil {
+			return err
+		}
+	}
+
+	if o.Password == "" {
+		if o.Password, err = cmdutil.PromptSecret("Password"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *LoginOptions) Run() error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	loginURL := strings.TrimRight(o.LoginURL, "/")
+
+	c := config.Context{
+		Company: o.Company,
+		User:    o.User,
+	}
+
+	name := o.Factory.ContextName
+	if name == "" {
+		name = strings.ToLower(o.Company)
+	}
+	c.Name = name
+	c.Credentials = name
+
+	cred := config.Credential{Password: o.Password}
+	if o.OAuth {
+		cred.AuthProvider = config.AuthProviderOAuth2
+		cred.OAuth2 = &config.OAuth2{
+			ClientID:     o.ClientID,
+			ClientSecret: o.ClientSecret,
+			RedirectURL:  o.RedirectURL,
+			Grant:        o.Grant,
+		}
+		if loginURL != defaultLoginURL {
+			cred.OAuth2.AuthURL = loginURL + "/auth/oauth2/authorize"
+			cred.OAuth2.TokenURL = loginURL + "/auth/oauth2/token"
+		}
+	}
+
+	httpClient, err := o.authenticate(ctx, &c, &cred)
+	if err != nil {
+		return err
+	}
+
+	info, err := discover(ctx, httpClient, loginURL)
+	if err != nil {
+		return err
+	}
+
+	c.BaseURL = info.Urls.Base
+	c.BulkURL = info.Urls.Apis.Rest.Bulk
+	c.RestURL = info.Urls.Apis.Rest.Standard
+	if c.Company == "" {
+		c.Company = info.Site.Name
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	// OAuth2 tokens are cached, there is no need to keep the password
+	if o.OAuth && cred.OAuth2.Grant != config.GrantPassword {
+		cred.Password = ""
+	}
+
+	if o.CredentialSource == config.SourceInline {
+		if cred.Password != "" || (cred.OAuth2 != nil && cred.OAuth2.ClientSecret != "") {
+			p, _ := config.Path()
+			fmt.Fprintf(os.Stderr, "Warning: the secrets are saved in plain text to %s, use --credential-source=keyring or file to keep them out of it.\n", p)
+		}
+	} else {
+		secrets := &credentials.Secrets{Password: cred.Password}
+		if cred.OAuth2 != nil {
+			secrets.ClientSecret = cred.OAuth2.ClientSecret
+			cred.OAuth2.ClientSecret = ""
+		}
+		cred.Password = ""
+		cred.Source = &config.CredentialSource{Type: o.CredentialSource, Path: o.CredentialPath}
+
+		if err := credentials.Store(name, &cred, secrets); err != nil {
+			return err
+		}
+	}
+
+	cfg.SetContext(name, c)
+	cfg.SetCredential(name, cred)
+	cfg.CurrentContext = name
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Logged in to %s (%s), context %q saved and set as current.\n", info.Site.Name, c.BaseURL, name)
+	return nil
+}
+
+// authenticate returns an *http.Client for the discovery request.
+// For OAuth2 it obtains a token and caches it for the context.
+func (o *LoginOptions) authenticate(ctx context.Context, c *config.Context, cred *config.Credential) (*http.Client, error) {
+	base, err := o.Factory.BaseClient()
+	if err != nil {
+		return nil, err
+	}
+
+	if !o.OAuth {
+		return factory.NewAuthClient(c, cred, base)
+	}
+
+	oc, err := factory.NewOAuth2Config(cred, base)
+	if err != nil {
+		return nil, err
+	}
+
+	tok, err := factory.OAuth2Login(ctx, oc, c, cred)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := config.TokenCachePath(c.Name)
+	if err != nil {
+		return nil, err
+	}
+	store := &oauth.FileStore{Path: p}
+	if err := store.Save(tok); err != nil {
+		return nil, err
+	}
+
+	tr := oauth.NewTransport(oc, store, tok)
+	tr.Transport = base.Transport
+	return &http.Client{Transport: tr, Timeout: base.Timeout}, nil
+}
+
+// discover calls the login id endpoint which describes the instance
+// the user belongs to, including the pod specific base URLs.
+func discover(ctx context.Context, client *http.Client, loginURL string) (*bulk.AccountInfo, error) {
+	req, err := http.NewRequest("GET", loginURL+"/id", nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &bulk.AccountInfo{}
+	// Eloqua answers with a JSON string, e.g. "Not authenticated.", when login fails
+	if err := json.Unmarshal(data, info); err != nil || resp.StatusCode != http.StatusOK {
+		var msg string
+		if json.Unmarshal(data, &msg) != nil || msg == "" {
+			msg = http.StatusText(resp.StatusCode)
+		}
+		return nil, &cmdutil.AuthError{Err: fmt.Errorf("login failed: %s", msg)}
+	}
+
+	if info.Urls.Apis.Rest.Bulk == "" || info.Urls.Apis.Rest.Standard == "" {
+		return nil, errors.New("login failed: the id endpoint did not return the API base URLs")
+	}
+
+	return info, nil
+}
@@ -0,0 +1,161 @@
+package login
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elqx/eloquactl/pkg/config"
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+)
+
+const idResponse = `{
+	"site": {"id": 42, "name": "AcmeCorp"},
+	"urls": {
+		"base": "https://secure.p03.eloqua.com",
+		"apis": {"rest": {
+			"standard": "https://secure.p03.eloqua.com/API/REST/{version}/",
+			"bulk": "https://secure.p03.eloqua.com/API/BULK/{version}/"
+		}}
+	}
+}`
+
+// idServer serves the login id endpoint to user Acme\bob with password pw.
+func idServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/id" {
+			http.NotFound(w, r)
+			return
+		}
+		if user, password, ok := r.BasicAuth(); !ok || user != `Acme\bob` || password != "pw" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`"Not authenticated."`))
+			return
+		}
+		w.Write([]byte(idResponse))
+	}))
+}
+
+func basicClient(user, password string) *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(user+":"+password)))
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestDiscover(t *testing.T) {
+	srv := idServer()
+	defer srv.Close()
+
+	empty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"site": {"name": "AcmeCorp"}}`))
+	}))
+	defer empty.Close()
+
+	tests := []struct {
+		name        string
+		loginURL    string
+		password    string
+		wantErr     bool
+		wantAuthErr bool
+	}{
+		{name: "authenticated", loginURL: srv.URL, password: "pw"},
+		{name: "wrong password", loginURL: srv.URL, password: "nope", wantErr: true, wantAuthErr: true},
+		{name: "no base URLs", loginURL: empty.URL, password: "pw", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := discover(context.Background(), basicClient(`Acme\bob`, tt.password), tt.loginURL)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("discover() = %+v, want an error", info)
+				}
+				if _, ok := err.(*cmdutil.AuthError); ok != tt.wantAuthErr {
+					t.Errorf("discover() = %T %v, auth error %v", err, err, tt.wantAuthErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Site.Name != "AcmeCorp" || info.Urls.Apis.Rest.Bulk != "https://secure.p03.eloqua.com/API/BULK/{version}/" {
+				t.Errorf("discover() = %+v", info)
+			}
+		})
+	}
+}
+
+func TestLoginRun(t *testing.T) {
+	srv := idServer()
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "eloquactl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	config.SetPath(filepath.Join(dir, "config.yaml"))
+	defer config.SetPath("")
+
+	// requests to the default login URL are sent to the test server
+	target, _ := url.Parse(srv.URL)
+	f := factory.New()
+	f.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host != "login.eloqua.com" {
+			t.Errorf("request sent to %s, want login.eloqua.com", req.URL)
+		}
+		req.URL.Scheme = target.Scheme
+		req.URL.Host = target.Host
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	o := &LoginOptions{
+		Factory:          f,
+		Company:          "Acme",
+		User:             "bob",
+		Password:         "pw",
+		CredentialSource: config.SourceInline,
+		LoginURL:         defaultLoginURL,
+	}
+	if err := o.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CurrentContext != "acme" {
+		t.Errorf("current context = %q, want acme", cfg.CurrentContext)
+	}
+	c, err := cfg.Context("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := config.Context{
+		Name:        "acme",
+		Company:     "Acme",
+		User:        "bob",
+		Credentials: "acme",
+		BaseURL:     "https://secure.p03.eloqua.com",
+		BulkURL:     "https://secure.p03.eloqua.com/API/BULK/{version}/",
+		RestURL:     "https://secure.p03.eloqua.com/API/REST/{version}/",
+	}
+	if *c != want {
+		t.Errorf("context = %+v, want %+v", *c, want)
+	}
+}
@@ -22,22 +22,40 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"fmt"
 	"github.com/spf13/cobra"
-	"os"
 
+	"github.com/elqx/eloquactl/cmd/action"
+	"github.com/elqx/eloquactl/cmd/appcloud"
+	cmdcache "github.com/elqx/eloquactl/cmd/cache"
+	cmdconfig "github.com/elqx/eloquactl/cmd/config"
+	"github.com/elqx/eloquactl/cmd/content"
 	"github.com/elqx/eloquactl/cmd/decide"
 	"github.com/elqx/eloquactl/cmd/export"
 	"github.com/elqx/eloquactl/cmd/feed"
 	"github.com/elqx/eloquactl/cmd/get"
 	"github.com/elqx/eloquactl/cmd/importt"
+	"github.com/elqx/eloquactl/cmd/login"
+	"github.com/elqx/eloquactl/cmd/serve"
+	"github.com/elqx/eloquactl/cmd/syncs"
+	"github.com/elqx/eloquactl/pkg/config"
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
 	"github.com/elqx/eloquactl/pkg/util/templates"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
 )
 
-var cfgFile string
+var (
+	cfgFile string
+
+	// f builds API clients for all the commands
+	f = factory.New()
+
+	// started is set once the arguments are parsed and a command runs,
+	// errors returned before that are usage errors
+	started bool
+)
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -47,45 +65,65 @@ var rootCmd = &cobra.Command{
 administrators to do administartive tasks, integration and App Cloud Application
 developers to manage data imports and exports to and from Eloqua and
 custom Feeder, Decision and Action services.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
-		os.Exit(1)
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		started = true
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Errors are printed to stderr and the process exits with the error's exit code.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	cmd, err := rootCmd.ExecuteC()
+	if err != nil && !started {
+		err = cmdutil.ValidationErrorf("%v\nSee '%s -h' for help and examples", err, cmd.CommandPath())
 	}
+	cmdutil.CheckErr(err)
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.eloquactl.yaml)")
+	f.AddFlags(rootCmd.PersistentFlags())
 
 	groups := templates.CommandGroups{
 		{
 			Message: "Rest API Commands",
 			Commands: []*cobra.Command{
-				get.NewCmdGet(),
+				get.NewCmdGet(f),
 			},
 		},
 		{
 			Message: "Bulk API commands",
 			Commands: []*cobra.Command{
-				export.NewCmdExport(),
-				importt.NewCmdImport(),
+				export.NewCmdExport(f),
+				importt.NewCmdImport(f),
+				syncs.NewCmdSyncs(f),
 			},
 		},
 		{
 			Message: "App Cloud Developer commands",
 			Commands: []*cobra.Command{
-				feed.NewCmdFeed(),
-				decide.NewCmdDecide(),
+				feed.NewCmdFeed(f),
+				decide.NewCmdDecide(f),
+				action.NewCmdAction(f),
+				content.NewCmdContent(f),
+				serve.NewCmdServe(f),
+				appcloud.NewCmdAppCloud(f),
+			},
+		},
+		{
+			Message: "Settings Commands",
+			Commands: []*cobra.Command{
+				cmdconfig.NewCmdConfig(),
+				cmdcache.NewCmdCache(f),
+				login.NewCmdLogin(f),
 			},
 		},
 	}
@@ -99,13 +137,11 @@ func initConfig() {
 	if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
+		config.SetPath(cfgFile)
 	} else {
 		// Find home directory.
 		home, err := homedir.Dir()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
+		cmdutil.CheckErr(err)
 
 		// Search config in home directory with name ".eloquactl" (without extension).
 		viper.AddConfigPath(home)
@@ -0,0 +1,152 @@
+package serve
+
+import (
+	"errors"
+	"log"
+	"os"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/appcloud"
+	"github.com/elqx/eloquactl/pkg/config"
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveLong = templates.LongDesc(`
+		Host App Cloud feeder, decision, action and content services.
+
+		The services are described in a YAML file. Each service is served under its path,
+		/<name> by default, with the create, configure, notify and delete endpoints Eloqua
+		calls for its instances, e.g. /lead-scorer/notify?instance={InstanceId}&execution={ExecutionId}.
+		Register these URLs in the service. Calls are checked against the OAuth1 signature
+		made with the client id and secret of the app. Instances are kept in a local store.
+
+		The records of every notify call are passed as JSON to the handler of the service:
+		a command reading them on stdin, a webhook or a Go plugin exporting
+		Notify func([]byte) ([]byte, error). Content services respond with the HTML the
+		handler returns. Other services respond asynchronously: the handler returns the
+		records by status, e.g. {"yes": [{"EmailAddress": "..."}]}, and they are imported
+		to the instance as eloquactl feed and decide do, to the current context.
+
+		Note that --config is the services file here, not the eloquactl config file.`)
+
+	serveExample = templates.Examples(`
+		# Serve the services of services.yaml
+		eloquactl serve --config services.yaml
+
+		# services.yaml
+		listen: ":8080"
+		baseURL: https://apps.example.com
+		services:
+		- name: lead-scorer
+		  type: decision
+		  clientId: 8d6a1c2e-...
+		  clientSecretEnv: LEAD_SCORER_SECRET
+		  handler:
+		    command: ["./score.sh"]
+		    timeout: 1m
+
+		# Serve without checking signatures, e.g. with eloquactl appcloud simulate
+		eloquactl serve --config services.yaml --listen=localhost:8080 --insecure`)
+)
+
+type ServeOptions struct {
+	Client    func() (*bulk.BulkClient, error)
+	SyncFlags *cmdutil.SyncFlags
+
+	ConfigFile     string
+	Listen         string
+	Store          string
+	KeepDefinition bool
+	Insecure       bool
+}
+
+func NewServeOptions(f *factory.Factory) *ServeOptions {
+	return &ServeOptions{
+		Client:    f.BulkClient,
+		SyncFlags: cmdutil.NewSyncFlags(),
+	}
+}
+
+func NewCmdServe(f *factory.Factory) *cobra.Command {
+	o := NewServeOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "serve --config=FILENAME",
+		Short:   "Host App Cloud services",
+		Long:    serveLong,
+		Example: serveExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run()
+		},
+	}
+
+	o.SyncFlags.AddFlags(cmd)
+	cmd.Flags().StringVar(&o.ConfigFile, "config", "", "The YAML file describing the services.")
+	cmd.Flags().StringVar(&o.Listen, "listen", "", "The address to listen on. Overrides listen of the services file.")
+	cmd.Flags().StringVar(&o.Store, "store", "", "The file keeping the instances. Overrides store of the services file.")
+	cmd.Flags().BoolVar(&o.KeepDefinition, "keep-definition", false, "If true, the import definitions of the responses are not deleted when the server is stopped.")
+	cmd.Flags().BoolVar(&o.Insecure, "insecure", false, "If true, calls are not checked against their signature.")
+
+	return cmd
+}
+
+func (o *ServeOptions) Validate() error {
+	if o.ConfigFile == "" {
+		return errors.New("you must specify the services file with --config")
+	}
+
+	return o.SyncFlags.Validate()
+}
+
+func (o *ServeOptions) Run() error {
+	c, err := appcloud.LoadConfig(o.ConfigFile)
+	if err != nil {
+		return &cmdutil.ValidationError{Err: err}
+	}
+
+	if o.Listen != "" {
+		c.Listen = o.Listen
+	}
+	if o.Store != "" {
+		c.Store = o.Store
+	}
+	if c.Store == "" {
+		c.Store, err = config.InstanceStorePath()
+		if err != nil {
+			return err
+		}
+	}
+
+	store, err := appcloud.OpenStore(c.Store)
+	if err != nil {
+		return err
+	}
+
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	logger.Printf("Instances are kept in %s", c.Store)
+	if o.Insecure {
+		logger.Printf("Warning: calls are not checked against their signature")
+	}
+
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+
+	s := &appcloud.Server{
+		Config:         c,
+		Store:          store,
+		Client:         o.Client,
+		SyncFlags:      o.SyncFlags,
+		KeepDefinition: o.KeepDefinition,
+		Insecure:       o.Insecure,
+		Log:            logger,
+	}
+	return s.ListenAndServe(ctx)
+}
@@ -0,0 +1,85 @@
+package syncs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/factory"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncsLong = templates.LongDesc(`
+		Inspect Bulk API syncs: their status, the linked export or import definition,
+		log entries and the records rejected by import syncs.
+
+		A sync is given by its id or uri, e.g. 123 or /syncs/123.`)
+)
+
+func NewCmdSyncs(f *factory.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "syncs SUBCOMMAND",
+		Aliases: []string{"sync"},
+		Short:   "Inspect Bulk API syncs",
+		Long:    syncsLong,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdutil.ValidationErrorf("you must specify a subcommand. See 'eloquactl syncs -h' for help and examples")
+		},
+	}
+
+	// create subcommands
+	cmd.AddCommand(NewCmdSyncsList(f))
+	cmd.AddCommand(NewCmdSyncsGet(f))
+	cmd.AddCommand(NewCmdSyncsLogs(f))
+	cmd.AddCommand(NewCmdSyncsRejects(f))
+
+	return cmd
+}
+
+// syncID returns the id of the sync given as the only argument.
+func syncID(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, cmdutil.ValidationErrorf("you must specify the id or uri of a sync")
+	}
+
+	id, err := bulkapi.SyncID(args[0])
+	if err != nil {
+		return 0, cmdutil.ValidationErrorf("invalid sync %q", args[0])
+	}
+	return id, nil
+}
+
+// getSync returns the sync. With watch it waits for the sync to finish,
+// reporting its status on stderr.
+func getSync(ctx context.Context, client *bulk.BulkClient, id int, watch bool, sf *cmdutil.SyncFlags) (*bulk.Sync, error) {
+	sync, err := client.Syncs.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync %d: %w", id, err)
+	}
+
+	if !watch {
+		return sync, nil
+	}
+
+	progress := cmdutil.NewProgress(sync.Uri)
+	progress.Unit = ""
+	defer progress.Done()
+	return cmdutil.WaitSync(ctx, client, sync, sf, progress)
+}
+
+// syncResult returns the error for the final status of a watched sync,
+// so that scripts can tell a failed sync by the exit code.
+func syncResult(sync *bulk.Sync) error {
+	switch sync.Status {
+	case bulkapi.SyncError:
+		return &cmdutil.SyncError{Uri: sync.Uri, Err: errors.New("failed")}
+	case bulkapi.SyncWarning:
+		return &cmdutil.PartialError{Err: fmt.Errorf("sync %s finished with warnings", sync.Uri)}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package syncs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/printers"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncsGetExample = templates.Examples(`
+		# Show the status and the definition uri of a sync
+		eloquactl syncs get 123
+
+		# Follow a running sync until it is done
+		eloquactl syncs get /syncs/123 --watch
+
+		# Print the sync and its definition as JSON
+		eloquactl syncs get 123 -o json`)
+)
+
+// syncDetails is a sync with the definition it synced.
+type syncDetails struct {
+	*bulk.Sync
+	Definition json.RawMessage `json:"definition,omitempty"`
+}
+
+type SyncsGetOptions struct {
+	Client     func() (*bulk.BulkClient, error)
+	PrintFlags *cmdutil.PrintFlags
+	SyncFlags  *cmdutil.SyncFlags
+
+	Watch bool
+}
+
+func NewSyncsGetOptions(f *factory.Factory) *SyncsGetOptions {
+	return &SyncsGetOptions{
+		Client:     f.BulkClient,
+		PrintFlags: cmdutil.NewPrintFlags(),
+		SyncFlags:  cmdutil.NewSyncFlags(),
+	}
+}
+
+func NewCmdSyncsGet(f *factory.Factory) *cobra.Command {
+	o := NewSyncsGetOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "get <ID>",
+		Short:   "Show the status and the definition of a sync",
+		Example: syncsGetExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := syncID(args)
+			if err != nil {
+				return err
+			}
+
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(id)
+		},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+	o.SyncFlags.AddFlags(cmd)
+	cmd.Flags().BoolVarP(&o.Watch, "watch", "w", false, "Wait for the sync to finish, reporting its status on stderr.")
+
+	return cmd
+}
+
+func (o *SyncsGetOptions) Validate() error {
+	if err := o.SyncFlags.Validate(); err != nil {
+		return err
+	}
+
+	return o.PrintFlags.Validate()
+}
+
+func (o *SyncsGetOptions) Run(id int) error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	sync, err := getSync(ctx, client, id, o.Watch, o.SyncFlags)
+	if err != nil {
+		return err
+	}
+
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+
+	// table and ndj printers work with lists, the definition is shown by uri
+	if _, ok := printer.(*printers.JsonPrinter); !ok {
+		w := printers.NewTabWriter(os.Stdout)
+		err = printer.PrintResource([]bulk.Sync{*sync}, w)
+		w.Flush()
+	} else {
+		d := &syncDetails{Sync: sync}
+		if sync.SyncedInstanceURI != "" {
+			d.Definition, err = bulkapi.GetDefinition(ctx, client, sync.SyncedInstanceURI)
+			if err != nil {
+				return fmt.Errorf("failed to get definition %s: %w", sync.SyncedInstanceURI, err)
+			}
+		}
+		err = printer.PrintResource(d, os.Stdout)
+	}
+	if err != nil {
+		return err
+	}
+
+	if o.Watch {
+		return syncResult(sync)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package syncs
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/printers"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncsListExample = templates.Examples(`
+		# List the 50 most recent syncs
+		eloquactl syncs list
+
+		# List failed syncs
+		eloquactl syncs list --search="status='error'" --limit=500`)
+)
+
+type SyncsListOptions struct {
+	Client     func() (*bulk.BulkClient, error)
+	PrintFlags *cmdutil.PrintFlags
+
+	Limit  int
+	Search string
+}
+
+func NewSyncsListOptions(f *factory.Factory) *SyncsListOptions {
+	return &SyncsListOptions{
+		Client:     f.BulkClient,
+		PrintFlags: cmdutil.NewPrintFlags(),
+	}
+}
+
+func NewCmdSyncsList(f *factory.Factory) *cobra.Command {
+	o := NewSyncsListOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the most recent syncs",
+		Example: syncsListExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run()
+		},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+	cmd.Flags().IntVar(&o.Limit, "limit", 50, "Maximum number of syncs to list.")
+	cmd.Flags().StringVar(&o.Search, "search", "", "Bulk API search expression, e.g. status='error'.")
+
+	return cmd
+}
+
+func (o *SyncsListOptions) Validate() error {
+	if o.Limit < 1 {
+		return fmt.Errorf("--limit must be positive")
+	}
+
+	return o.PrintFlags.Validate()
+}
+
+func (o *SyncsListOptions) Run() error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	syncs, err := bulkapi.ListSyncs(ctx, client, o.Search, o.Limit)
+	if err != nil {
+		return fmt.Errorf("failed to list syncs: %w", err)
+	}
+
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	w := printers.NewTabWriter(os.Stdout)
+	defer w.Flush()
+
+	return printer.PrintResource(syncs, w)
+}
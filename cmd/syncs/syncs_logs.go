@@ -0,0 +1,112 @@
+package syncs
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/printers"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncsLogsExample = templates.Examples(`
+		# Print the log entries of a sync
+		eloquactl syncs logs 123
+
+		# Wait for a running sync and print its log entries
+		eloquactl syncs logs 123 --watch`)
+)
+
+type SyncsLogsOptions struct {
+	Client     func() (*bulk.BulkClient, error)
+	PrintFlags *cmdutil.PrintFlags
+	SyncFlags  *cmdutil.SyncFlags
+
+	Watch bool
+}
+
+func NewSyncsLogsOptions(f *factory.Factory) *SyncsLogsOptions {
+	return &SyncsLogsOptions{
+		Client:     f.BulkClient,
+		PrintFlags: cmdutil.NewPrintFlags(),
+		SyncFlags:  cmdutil.NewSyncFlags(),
+	}
+}
+
+func NewCmdSyncsLogs(f *factory.Factory) *cobra.Command {
+	o := NewSyncsLogsOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "logs <ID>",
+		Aliases: []string{"log"},
+		Short:   "Print the log entries of a sync",
+		Example: syncsLogsExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := syncID(args)
+			if err != nil {
+				return err
+			}
+
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(id)
+		},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+	o.SyncFlags.AddFlags(cmd)
+	cmd.Flags().BoolVarP(&o.Watch, "watch", "w", false, "Wait for the sync to finish before printing the log entries.")
+
+	return cmd
+}
+
+func (o *SyncsLogsOptions) Validate() error {
+	if err := o.SyncFlags.Validate(); err != nil {
+		return err
+	}
+
+	return o.PrintFlags.Validate()
+}
+
+func (o *SyncsLogsOptions) Run(id int) error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	var sync *bulk.Sync
+	if o.Watch {
+		if sync, err = getSync(ctx, client, id, true, o.SyncFlags); err != nil {
+			return err
+		}
+	}
+
+	logs, err := bulkapi.SyncLogs(ctx, client, id)
+	if err != nil {
+		return fmt.Errorf("failed to get sync logs: %w", err)
+	}
+
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	w := printers.NewTabWriter(os.Stdout)
+	if err := printer.PrintResource(logs, w); err != nil {
+		return err
+	}
+	w.Flush()
+
+	if sync != nil {
+		return syncResult(sync)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package syncs
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/factory"
+	"github.com/elqx/eloquactl/pkg/printers"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+	"github.com/elqx/eloquactl/pkg/util/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncsRejectsExample = templates.Examples(`
+		# Print the records rejected by an import sync
+		eloquactl syncs rejects 123
+
+		# Wait for a running import sync and print the rejected records
+		eloquactl syncs rejects 123 --watch`)
+)
+
+type SyncsRejectsOptions struct {
+	Client     func() (*bulk.BulkClient, error)
+	PrintFlags *cmdutil.PrintFlags
+	SyncFlags  *cmdutil.SyncFlags
+
+	Watch bool
+}
+
+func NewSyncsRejectsOptions(f *factory.Factory) *SyncsRejectsOptions {
+	return &SyncsRejectsOptions{
+		Client:     f.BulkClient,
+		PrintFlags: cmdutil.NewPrintFlags(),
+		SyncFlags:  cmdutil.NewSyncFlags(),
+	}
+}
+
+func NewCmdSyncsRejects(f *factory.Factory) *cobra.Command {
+	o := NewSyncsRejectsOptions(f)
+
+	cmd := &cobra.Command{
+		Use:     "rejects <ID>",
+		Aliases: []string{"reject"},
+		Short:   "Print the records rejected by an import sync",
+		Example: syncsRejectsExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := syncID(args)
+			if err != nil {
+				return err
+			}
+
+			if err := o.Validate(); err != nil {
+				return &cmdutil.ValidationError{Err: err}
+			}
+
+			return o.Run(id)
+		},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+	o.SyncFlags.AddFlags(cmd)
+	cmd.Flags().BoolVarP(&o.Watch, "watch", "w", false, "Wait for the sync to finish before printing the rejected records.")
+
+	return cmd
+}
+
+func (o *SyncsRejectsOptions) Validate() error {
+	if err := o.SyncFlags.Validate(); err != nil {
+		return err
+	}
+
+	return o.PrintFlags.Validate()
+}
+
+func (o *SyncsRejectsOptions) Run(id int) error {
+	ctx, cancel := cmdutil.InterruptContext()
+	defer cancel()
+	client, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	var sync *bulk.Sync
+	if o.Watch {
+		if sync, err = getSync(ctx, client, id, true, o.SyncFlags); err != nil {
+			return err
+		}
+	}
+
+	rejects, err := bulkapi.SyncRejects(ctx, client, id)
+	if err != nil {
+		return fmt.Errorf("failed to get rejected records: %w", err)
+	}
+
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	w := printers.NewTabWriter(os.Stdout)
+	if err := printer.PrintResource(rejects, w); err != nil {
+		return err
+	}
+	w.Flush()
+
+	if sync != nil {
+		return syncResult(sync)
+	}
+	return nil
+}
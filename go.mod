@@ -1,6 +1,6 @@
 module github.com/elqx/eloquactl
 
-go 1.12
+go 1.13
 
 require (
 	github.com/MakeNowJust/heredoc/v2 v2.0.1
@@ -9,4 +9,6 @@ require (
 	github.com/spf13/cobra v0.0.6
 	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.6.2
+	golang.org/x/crypto v0.0.0-20211117183948-ae814b36b871
+	gopkg.in/yaml.v2 v2.2.4
 )
@@ -0,0 +1,218 @@
+// Package appcloud helps developing App Cloud apps: it hosts services,
+// implementing the create, configure, notify and delete endpoints Eloqua
+// calls for the instances of feeder, decision, action and content services,
+// and keeps the registration of apps and services in manifests.
+package appcloud
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Service types.
+const (
+	TypeFeeder   = "feeder"
+	TypeDecision = "decision"
+	TypeAction   = "action"
+	TypeContent  = "content"
+)
+
+const (
+	DefaultListen         = ":8080"
+	DefaultHandlerTimeout = 5 * time.Minute
+
+	emailAddressStatement = "{{Contact.Field(C_EmailAddress)}}"
+)
+
+// statuses are the statuses a handler can set for the records of each
+// service type, the feeder one adding contacts to the campaign.
+var statuses = map[string][]string{
+	TypeFeeder:   {"add"},
+	TypeDecision: {"yes", "no"},
+	TypeAction:   {"complete", "errored"},
+}
+
+// Config describes the services hosted by eloquactl serve.
+type Config struct {
+	// Listen is the address to listen on, :8080 by default.
+	Listen string `yaml:"listen,omitempty"`
+	// BaseURL is the URL Eloqua calls the server at, e.g. behind a proxy.
+	// It is needed to check signatures, defaults to http://<Host header>.
+	BaseURL string `yaml:"baseURL,omitempty"`
+	// Store is the file keeping the instances, by default instances.json
+	// in the eloquactl directory.
+	Store    string    `yaml:"store,omitempty"`
+	Services []Service `yaml:"services"`
+}
+
+// Service is an App Cloud service registered in Eloqua. Its endpoints are
+// <path>/create, <path>/configure, <path>/notify and <path>/delete, with the
+// instance id in the instance query parameter and the execution id in the
+// execution one.
+type Service struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	// Path defaults to /<name>.
+	Path            string `yaml:"path,omitempty"`
+	ClientID        string `yaml:"clientId,omitempty"`
+	ClientSecret    string `yaml:"clientSecret,omitempty"`
+	ClientSecretEnv string `yaml:"clientSecretEnv,omitempty"`
+	// RecordDefinition is the contact fields sent in notify calls, by
+	// default the contact id and email address.
+	RecordDefinition map[string]string `yaml:"recordDefinition,omitempty"`
+	// Identifier is the record definition field contacts are matched by
+	// when responding, by default the email address one.
+	Identifier            string        `yaml:"identifier,omitempty"`
+	RequiresConfiguration bool          `yaml:"requiresConfiguration,omitempty"`
+	Handler               HandlerConfig `yaml:"handler"`
+}
+
+// HandlerConfig selects the handler of the notify calls of a service,
+// one of a command, a webhook and a Go plugin.
+type HandlerConfig struct {
+	// Command is run with the notification on stdin, its stdout is the result.
+	Command []string `yaml:"command,omitempty"`
+	// Webhook is a URL the notification is posted to, the response is the result.
+	Webhook string `yaml:"webhook,omitempty"`
+	// Plugin is a Go plugin exporting Notify, a func([]byte) ([]byte, error).
+	Plugin string `yaml:"plugin,omitempty"`
+	// Timeout is the time a notification may take, e.g. 30s.
+	Timeout string `yaml:"timeout,omitempty"`
+}
+
+// LoadConfig reads and validates the services file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Config{}
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	if err := c.complete(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return c, nil
+}
+
+// complete sets the defaults and validates the services.
+func (c *Config) complete() error {
+	if c.Listen == "" {
+		c.Listen = DefaultListen
+	}
+	c.BaseURL = strings.TrimSuffix(c.BaseURL, "/")
+
+	if len(c.Services) == 0 {
+		return errors.New("no services")
+	}
+
+	paths := map[string]string{}
+	for n := range c.Services {
+		s := &c.Services[n]
+		if s.Name == "" {
+			return fmt.Errorf("service %d has no name", n+1)
+		}
+
+		if err := s.complete(); err != nil {
+			return fmt.Errorf("service %s: %v", s.Name, err)
+		}
+
+		if other, ok := paths[s.Path]; ok {
+			return fmt.Errorf("services %s and %s have the same path %s", other, s.Name, s.Path)
+		}
+		paths[s.Path] = s.Name
+	}
+	return nil
+}
+
+func (s *Service) complete() error {
+	switch s.Type {
+	case TypeFeeder, TypeDecision, TypeAction, TypeContent:
+	default:
+		return fmt.Errorf("invalid type %q, expected one of: feeder|decision|action|content", s.Type)
+	}
+
+	if s.Path == "" {
+		s.Path = "/" + s.Name
+	}
+	if !strings.HasPrefix(s.Path, "/") {
+		return fmt.Errorf("path %q must start with /", s.Path)
+	}
+	s.Path = strings.TrimSuffix(s.Path, "/")
+
+	if s.ClientSecretEnv != "" {
+		s.ClientSecret = os.Getenv(s.ClientSecretEnv)
+		if s.ClientSecret == "" {
+			return fmt.Errorf("environment variable %s is not set", s.ClientSecretEnv)
+		}
+	}
+	if (s.ClientID == "") != (s.ClientSecret == "") {
+		return errors.New("clientId and clientSecret must be set together")
+	}
+
+	if len(s.RecordDefinition) == 0 {
+		s.RecordDefinition = map[string]string{
+			"ContactID":    "{{Contact.Id}}",
+			"EmailAddress": emailAddressStatement,
+		}
+	}
+
+	if s.Identifier == "" {
+		for field, statement := range s.RecordDefinition {
+			if statement == emailAddressStatement {
+				s.Identifier = field
+			}
+		}
+	}
+	if s.Type != TypeContent {
+		if s.Identifier == "" {
+			return errors.New("there is no email address in the record definition, set the identifier")
+		}
+		if _, ok := s.RecordDefinition[s.Identifier]; !ok {
+			return fmt.Errorf("identifier %q is not in the record definition", s.Identifier)
+		}
+	}
+
+	h := s.Handler
+	set := 0
+	for _, ok := range []bool{len(h.Command) > 0, h.Webhook != "", h.Plugin != ""} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("the handler must be one of command, webhook and plugin")
+	}
+
+	if _, err := s.HandlerTimeout(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// HandlerTimeout returns the time a notification may take.
+func (s *Service) HandlerTimeout() (time.Duration, error) {
+	if s.Handler.Timeout == "" {
+		return DefaultHandlerTimeout, nil
+	}
+
+	d, err := time.ParseDuration(s.Handler.Timeout)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid handler timeout %q", s.Handler.Timeout)
+	}
+	return d, nil
+}
+
+// Statuses returns the statuses the handler of the service can set.
+func (s *Service) Statuses() []string {
+	return statuses[s.Type]
+}
@@ -0,0 +1,139 @@
+package appcloud
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"plugin"
+	"strings"
+)
+
+// Notification is the payload passed to handlers: a page of the records
+// Eloqua sent to a service instance.
+type Notification struct {
+	Service      string              `json:"service"`
+	Type         string              `json:"type"`
+	Instance     string              `json:"instance"`
+	Execution    int                 `json:"execution,omitempty"`
+	Offset       int                 `json:"offset"`
+	Limit        int                 `json:"limit"`
+	TotalResults int                 `json:"totalResults"`
+	HasMore      bool                `json:"hasMore"`
+	Items        []map[string]string `json:"items"`
+}
+
+// Handler handles the JSON encoded notifications of a service. The result
+// of content services is HTML. The result of other services is a JSON object
+// of the records to respond with by status, e.g. {"yes": [...], "no": [...]}.
+type Handler interface {
+	Notify(ctx context.Context, notification []byte) ([]byte, error)
+}
+
+// NewHandler returns the handler described by the config.
+func NewHandler(c HandlerConfig) (Handler, error) {
+	switch {
+	case len(c.Command) > 0:
+		return &commandHandler{command: c.Command}, nil
+	case c.Webhook != "":
+		return &webhookHandler{url: c.Webhook, client: http.DefaultClient}, nil
+	case c.Plugin != "":
+		return openPlugin(c.Plugin)
+	}
+	return nil, errors.New("no handler")
+}
+
+// commandHandler runs a command with the notification on stdin.
+type commandHandler struct {
+	command []string
+}
+
+func (h *commandHandler) Notify(ctx context.Context, notification []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, h.command[0], h.command[1:]...)
+	cmd.Stdin = bytes.NewReader(notification)
+	cmd.Stderr = os.Stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", strings.Join(h.command, " "), err)
+	}
+	return out, nil
+}
+
+// webhookHandler posts the notification to a URL.
+type webhookHandler struct {
+	url    string
+	client *http.Client
+}
+
+func (h *webhookHandler) Notify(ctx context.Context, notification []byte) ([]byte, error) {
+	req, err := http.NewRequest("POST", h.url, bytes.NewReader(notification))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("%s: %s: %s", h.url, resp.Status, bytes.TrimSpace(body))
+	}
+	return body, nil
+}
+
+// pluginHandler calls the Notify function of a Go plugin.
+type pluginHandler struct {
+	notify func([]byte) ([]byte, error)
+}
+
+func openPlugin(path string) (*pluginHandler, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sym, err := p.Lookup("Notify")
+	if err != nil {
+		return nil, err
+	}
+
+	notify, ok := sym.(func([]byte) ([]byte, error))
+	if !ok {
+		return nil, fmt.Errorf("%s: Notify is a %T, expected a func([]byte) ([]byte, error)", path, sym)
+	}
+	return &pluginHandler{notify: notify}, nil
+}
+
+func (h *pluginHandler) Notify(ctx context.Context, notification []byte) ([]byte, error) {
+	type result struct {
+		out []byte
+		err error
+	}
+
+	c := make(chan result, 1)
+	go func() {
+		out, err := h.notify(notification)
+		c <- result{out, err}
+	}()
+
+	select {
+	case r := <-c:
+		return r.out, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,142 @@
+package appcloud
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/elqx/eloquactl/pkg/restapi"
+	"gopkg.in/yaml.v2"
+)
+
+// Manifest kinds.
+const (
+	KindApp     = "App"
+	KindService = "Service"
+)
+
+// Manifest is the apps and services described in YAML documents, e.g.
+//
+//	kind: App
+//	name: Lead Tools
+//	enableUrl: https://apps.example.com/enable
+//	---
+//	kind: Service
+//	app: Lead Tools
+//	type: decision
+//	name: Lead Scorer
+//	notificationUrl: https://apps.example.com/lead-scorer/notify?instance={InstanceId}
+type Manifest struct {
+	Apps     []restapi.App
+	Services []restapi.Service
+}
+
+// ReadManifests reads the apps and services of the files, "-" being stdin.
+func ReadManifests(files []string) (*Manifest, error) {
+	m := &Manifest{}
+	for _, file := range files {
+		var data []byte
+		var err error
+		if file == "-" {
+			data, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			data, err = ioutil.ReadFile(file)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		d := yaml.NewDecoder(strings.NewReader(string(data)))
+		for n := 1; ; n++ {
+			var doc map[string]interface{}
+			if err := d.Decode(&doc); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("%s: %v", file, err)
+			}
+			if len(doc) == 0 {
+				continue
+			}
+
+			if err := m.add(doc); err != nil {
+				return nil, fmt.Errorf("%s: document %d: %v", file, n, err)
+			}
+		}
+	}
+
+	return m, m.validate()
+}
+
+// add decodes a document by its kind.
+func (m *Manifest) add(doc map[string]interface{}) error {
+	kind, _ := doc["kind"].(string)
+	delete(doc, "kind")
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case KindApp:
+		a := restapi.App{}
+		if err := yaml.UnmarshalStrict(data, &a); err != nil {
+			return err
+		}
+		m.Apps = append(m.Apps, a)
+	case KindService:
+		s := restapi.Service{}
+		if err := yaml.UnmarshalStrict(data, &s); err != nil {
+			return err
+		}
+		m.Services = append(m.Services, s)
+	default:
+		return fmt.Errorf("invalid kind %q, expected %s or %s", kind, KindApp, KindService)
+	}
+	return nil
+}
+
+func (m *Manifest) validate() error {
+	apps := map[string]bool{}
+	for _, a := range m.Apps {
+		if a.Name == "" {
+			return errors.New("an app has no name")
+		}
+		if apps[a.Name] {
+			return fmt.Errorf("app %q is described twice", a.Name)
+		}
+		apps[a.Name] = true
+	}
+
+	services := map[string]bool{}
+	for _, s := range m.Services {
+		if s.App == "" || s.Name == "" {
+			return errors.New("a service has no app or name")
+		}
+		if !isServiceType(s.Type) {
+			return fmt.Errorf("service %q has invalid type %q, expected one of: %s", s.Name, s.Type, strings.Join(restapi.ServiceTypes, "|"))
+		}
+		if services[ServiceKey(&s)] {
+			return fmt.Errorf("service %q is described twice", ServiceKey(&s))
+		}
+		services[ServiceKey(&s)] = true
+	}
+	return nil
+}
+
+// ServiceKey identifies a service by the names of its app and itself.
+func ServiceKey(s *restapi.Service) string {
+	return s.App + "/" + s.Name
+}
+
+func isServiceType(t string) bool {
+	for _, st := range restapi.ServiceTypes {
+		if t == st {
+			return true
+		}
+	}
+	return false
+}
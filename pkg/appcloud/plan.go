@@ -0,0 +1,258 @@
+package appcloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/elqx/eloqua-go/eloqua/rest"
+	"github.com/elqx/eloquactl/pkg/restapi"
+	"gopkg.in/yaml.v2"
+)
+
+// Change actions.
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionUnchanged = "unchanged"
+)
+
+// Change is the difference between an app or service of a manifest and
+// its registration in Eloqua.
+type Change struct {
+	Kind   string
+	Name   string
+	Action string
+	// Local and Remote are *restapi.App or *restapi.Service, Remote is nil
+	// when the app or service is to be created.
+	Local  interface{}
+	Remote interface{}
+}
+
+// Remote is the apps and services registered in Eloqua.
+type Remote struct {
+	Apps     []restapi.App
+	Services []restapi.Service
+}
+
+// FetchRemote lists the apps and, if services is true, the services of all
+// of them, with the name of their app.
+func FetchRemote(ctx context.Context, client *rest.RestClient, services bool) (*Remote, error) {
+	apps, err := restapi.ListApps(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Remote{Apps: apps}
+	if !services {
+		return r, nil
+	}
+
+	for _, a := range apps {
+		s, err := restapi.ListServices(ctx, client, a.ID)
+		if err != nil {
+			return nil, err
+		}
+		for n := range s {
+			s[n].App = a.Name
+		}
+		r.Services = append(r.Services, s...)
+	}
+	return r, nil
+}
+
+// App returns the app with the given name, nil if there is none.
+func (r *Remote) App(name string) *restapi.App {
+	for n := range r.Apps {
+		if r.Apps[n].Name == name {
+			return &r.Apps[n]
+		}
+	}
+	return nil
+}
+
+// Service returns the service with the given key, nil if there is none.
+func (r *Remote) Service(key string) *restapi.Service {
+	for n := range r.Services {
+		if ServiceKey(&r.Services[n]) == key {
+			return &r.Services[n]
+		}
+	}
+	return nil
+}
+
+// Plan returns the changes applying the apps or services of the manifest,
+// depending on kind, would make.
+func Plan(m *Manifest, r *Remote, kind string) []Change {
+	var changes []Change
+	switch kind {
+	case KindApp:
+		for n := range m.Apps {
+			local := m.Apps[n]
+			c := Change{Kind: KindApp, Name: local.Name, Action: ActionCreate, Local: &local}
+			if remote := r.App(local.Name); remote != nil {
+				local.ID = remote.ID
+				c.Remote = remote
+				c.Action = action(&local, remote)
+			}
+			changes = append(changes, c)
+		}
+	case KindService:
+		for n := range m.Services {
+			local := m.Services[n]
+			c := Change{Kind: KindService, Name: ServiceKey(&local), Action: ActionCreate, Local: &local}
+			if remote := r.Service(c.Name); remote != nil {
+				local.ID, local.AppID = remote.ID, remote.AppID
+				c.Remote = remote
+				c.Action = action(&local, remote)
+			}
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+func action(local, remote interface{}) string {
+	l, r := Compared(local, remote)
+	if l == r {
+		return ActionUnchanged
+	}
+	return ActionUpdate
+}
+
+// Compared returns the renderings of an app or service of a manifest and of
+// its registration which are compared. Only the fields the manifest sets
+// are rendered, the values Eloqua fills in or defaults are left out.
+func Compared(local, remote interface{}) (string, string) {
+	l := Render(local)
+
+	var fields yaml.MapSlice
+	if err := yaml.Unmarshal([]byte(l), &fields); err != nil {
+		return l, Render(remote)
+	}
+	set := map[interface{}]bool{}
+	for _, f := range fields {
+		set[f.Key] = true
+	}
+
+	var all, r yaml.MapSlice
+	if err := yaml.Unmarshal([]byte(Render(remote)), &all); err != nil {
+		return l, Render(remote)
+	}
+	for _, f := range all {
+		if set[f.Key] {
+			r = append(r, f)
+		}
+	}
+	if len(r) == 0 {
+		return l, ""
+	}
+
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return l, Render(remote)
+	}
+	return l, string(data)
+}
+
+// Render returns an app or service as a YAML document without its id, as
+// it is compared and diffed. nil is rendered as an empty string.
+func Render(v interface{}) string {
+	var doc interface{}
+	switch v := v.(type) {
+	case *restapi.App:
+		if v == nil {
+			return ""
+		}
+		c := *v
+		c.ID = ""
+		doc = &c
+	case *restapi.Service:
+		if v == nil {
+			return ""
+		}
+		c := *v
+		c.ID = ""
+		doc = &c
+	default:
+		return ""
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// Apply makes the changes. Services are added to the apps of r, which must
+// exist. report is called after every change.
+func Apply(ctx context.Context, client *rest.RestClient, changes []Change, r *Remote, report func(Change)) error {
+	// check the apps of the services before changing anything
+	for _, c := range changes {
+		if s, ok := c.Local.(*restapi.Service); ok && r.App(s.App) == nil {
+			return fmt.Errorf("app %q of service %q does not exist, apply the app first", s.App, s.Name)
+		}
+	}
+
+	for _, c := range changes {
+		if c.Action == ActionUnchanged {
+			report(c)
+			continue
+		}
+
+		switch local := c.Local.(type) {
+		case *restapi.App:
+			if c.Action == ActionCreate {
+				a, err := restapi.CreateApp(ctx, client, local)
+				if err != nil {
+					return fmt.Errorf("failed to create app %q: %w", c.Name, err)
+				}
+				r.Apps = append(r.Apps, *a)
+			} else {
+				a := &restapi.App{}
+				if err := merge(a, c.Remote, local); err != nil {
+					return err
+				}
+				if _, err := restapi.UpdateApp(ctx, client, a); err != nil {
+					return fmt.Errorf("failed to update app %q: %w", c.Name, err)
+				}
+			}
+		case *restapi.Service:
+			local.AppID = r.App(local.App).ID
+
+			if c.Action == ActionCreate {
+				if _, err := restapi.CreateService(ctx, client, local); err != nil {
+					return fmt.Errorf("failed to create service %q: %w", c.Name, err)
+				}
+			} else {
+				s := &restapi.Service{}
+				if err := merge(s, c.Remote, local); err != nil {
+					return err
+				}
+				if _, err := restapi.UpdateService(ctx, client, s); err != nil {
+					return fmt.Errorf("failed to update service %q: %w", c.Name, err)
+				}
+			}
+		}
+		report(c)
+	}
+	return nil
+}
+
+// merge sets v to the registration with the fields the manifest sets
+// replaced, so that updating keeps the values of the fields left out, as
+// diff compares them.
+func merge(v, remote, local interface{}) error {
+	for _, doc := range []interface{}{remote, local} {
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		// fields missing from the document keep their value
+		if err := json.Unmarshal(data, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,375 @@
+package appcloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/oauth1"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+)
+
+const (
+	contactImportsPath = "/contacts/imports"
+	shutdownTimeout    = 30 * time.Second
+
+	// maxNotifySize bounds the body of notify calls. Eloqua sends up to 5000
+	// records per call, well below it.
+	maxNotifySize = 32 << 20
+	// maxNameLength is the longest name of an import definition.
+	maxNameLength = 100
+)
+
+// Server serves the endpoints of the services of a config. Notifications of
+// content services are answered with the HTML of the handler. Other services
+// answer asynchronously: the records returned by the handler are imported
+// with the sync action of their status, as eloquactl feed and decide do.
+type Server struct {
+	Config *Config
+	Store  *Store
+	Client func() (*bulk.BulkClient, error)
+	// SyncFlags control waiting for the syncs of the responses.
+	SyncFlags *cmdutil.SyncFlags
+	// KeepDefinition keeps the import definitions of the responses when the
+	// server is stopped in the middle of a sync.
+	KeepDefinition bool
+	// Insecure accepts unsigned calls, e.g. when testing locally.
+	Insecure bool
+	Log      *log.Logger
+
+	ctx       context.Context
+	handlers  map[string]Handler
+	verifiers map[string]*oauth1.Verifier
+	pending   sync.WaitGroup
+}
+
+// ListenAndServe serves the services until ctx is done. Responses in
+// progress are then canceled, their import definitions deleted.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	s.ctx = ctx
+	s.handlers = map[string]Handler{}
+	s.verifiers = map[string]*oauth1.Verifier{}
+
+	mux := http.NewServeMux()
+	for n := range s.Config.Services {
+		svc := &s.Config.Services[n]
+
+		h, err := NewHandler(svc.Handler)
+		if err != nil {
+			return fmt.Errorf("service %s: %v", svc.Name, err)
+		}
+		s.handlers[svc.Name] = h
+
+		switch {
+		case s.Insecure:
+		case svc.ClientID != "":
+			s.verifiers[svc.Name] = oauth1.NewVerifier(svc.ClientID, svc.ClientSecret)
+		default:
+			return fmt.Errorf("service %s has no clientId to check signatures with, use --insecure to accept unsigned calls", svc.Name)
+		}
+
+		mux.HandleFunc(svc.Path+"/create", s.endpoint(svc, "POST", s.create))
+		mux.HandleFunc(svc.Path+"/configure", s.endpoint(svc, "GET", s.configure))
+		mux.HandleFunc(svc.Path+"/notify", s.endpoint(svc, "POST", s.notify))
+		mux.HandleFunc(svc.Path+"/delete", s.endpoint(svc, "DELETE", s.delete))
+		s.Log.Printf("Serving %s service %s at %s", svc.Type, svc.Name, svc.Path)
+	}
+
+	srv := &http.Server{Addr: s.Config.Listen, Handler: mux}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.ListenAndServe()
+	}()
+	s.Log.Printf("Listening on %s", s.Config.Listen)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.Log.Printf("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err := srv.Shutdown(shutdownCtx)
+	s.pending.Wait()
+	return err
+}
+
+type endpointFunc func(w http.ResponseWriter, r *http.Request, svc *Service, instance string)
+
+// endpoint checks the method, the signature and the instance of a call.
+func (s *Server) endpoint(svc *Service, method string, fn endpointFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if v, ok := s.verifiers[svc.Name]; ok {
+			params, err := oauth1.RequestParams(r)
+			if err == nil {
+				err = v.Verify(r.Method, s.requestURL(r), params)
+			}
+			if err != nil {
+				s.Log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+				http.Error(w, "invalid signature", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		instance := r.URL.Query().Get("instance")
+		if err := cmdutil.ValidateInstance(instance); err != nil {
+			http.Error(w, "invalid or missing instance query parameter", http.StatusBadRequest)
+			return
+		}
+
+		fn(w, r, svc, instance)
+	}
+}
+
+// requestURL returns the URL Eloqua called, without the query.
+func (s *Server) requestURL(r *http.Request) string {
+	if s.Config.BaseURL != "" {
+		return s.Config.BaseURL + r.URL.EscapedPath()
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + r.URL.EscapedPath()
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, svc *Service, instance string) {
+	if err := s.Store.Create(svc.Name, instance); err != nil {
+		s.Log.Printf("%s %s: failed to store instance: %v", svc.Name, instance, err)
+		http.Error(w, "failed to store instance", http.StatusInternalServerError)
+		return
+	}
+	s.Log.Printf("%s %s: created", svc.Name, instance)
+
+	writeJSON(w, map[string]interface{}{
+		"recordDefinition":      svc.RecordDefinition,
+		"requiresConfiguration": svc.RequiresConfiguration,
+	})
+}
+
+func (s *Server) configure(w http.ResponseWriter, r *http.Request, svc *Service, instance string) {
+	var createdAt time.Time
+	err := s.Store.Update(svc.Name, instance, func(i *Instance) {
+		now := time.Now()
+		i.ConfiguredAt = &now
+		createdAt = i.CreatedAt
+	})
+	if err == ErrUnknownInstance {
+		http.Error(w, "unknown instance", http.StatusNotFound)
+		return
+	} else if err != nil {
+		s.Log.Printf("%s %s: failed to store instance: %v", svc.Name, instance, err)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html><body><p>Instance %s of the %s service %s, created %s.</p></body></html>\n",
+		instance, svc.Type, svc.Name, createdAt.Format(time.RFC1123))
+}
+
+func (s *Server) notify(w http.ResponseWriter, r *http.Request, svc *Service, instance string) {
+	if s.Store.Get(svc.Name, instance) == nil {
+		http.Error(w, "unknown instance", http.StatusNotFound)
+		return
+	}
+
+	n := &Notification{Service: svc.Name, Type: svc.Type, Instance: instance}
+	if e := r.URL.Query().Get("execution"); e != "" {
+		execution, err := strconv.Atoi(e)
+		if err != nil || execution < 0 {
+			http.Error(w, "invalid execution query parameter", http.StatusBadRequest)
+			return
+		}
+		n.Execution = execution
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxNotifySize))
+	if err != nil {
+		// MaxBytesReader fails once the limit is read
+		if len(body) >= maxNotifySize {
+			http.Error(w, "the request is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "failed to read the request", http.StatusBadRequest)
+		return
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, n); err != nil {
+			http.Error(w, "invalid notification: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	// the query is authoritative, the body cannot change them
+	n.Service, n.Type, n.Instance = svc.Name, svc.Type, instance
+
+	err = s.Store.Update(svc.Name, instance, func(i *Instance) {
+		now := time.Now()
+		i.NotifiedAt = &now
+		i.Notifications++
+	})
+	if err == ErrUnknownInstance {
+		// deleted while the notification was read
+		http.Error(w, "unknown instance", http.StatusNotFound)
+		return
+	} else if err != nil {
+		s.Log.Printf("%s %s: failed to store instance: %v", svc.Name, instance, err)
+	}
+	s.Log.Printf("%s %s: notified of %d records", svc.Name, instance, len(n.Items))
+
+	if svc.Type == TypeContent {
+		out, err := s.handle(s.ctx, svc, n)
+		if err != nil {
+			s.Log.Printf("%s %s: %v", svc.Name, instance, err)
+			http.Error(w, "the handler failed", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(out)
+		return
+	}
+
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		if err := s.respond(svc, n); err != nil {
+			s.Log.Printf("%s %s: %v", svc.Name, instance, err)
+		}
+	}()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request, svc *Service, instance string) {
+	if err := s.Store.Delete(svc.Name, instance); err != nil {
+		s.Log.Printf("%s %s: failed to delete instance: %v", svc.Name, instance, err)
+		http.Error(w, "failed to delete instance", http.StatusInternalServerError)
+		return
+	}
+	s.Log.Printf("%s %s: deleted", svc.Name, instance)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handle passes the notification to the handler of the service.
+func (s *Server) handle(ctx context.Context, svc *Service, n *Notification) ([]byte, error) {
+	timeout, err := svc.HandlerTimeout()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return nil, err
+	}
+	return s.handlers[svc.Name].Notify(ctx, payload)
+}
+
+// respond imports the records returned by the handler with the sync action
+// of their status.
+func (s *Server) respond(svc *Service, n *Notification) error {
+	out, err := s.handle(s.ctx, svc, n)
+	if err != nil {
+		return err
+	}
+
+	var result map[string][]map[string]interface{}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return fmt.Errorf("invalid handler result: %v", err)
+	}
+
+	known := map[string]bool{}
+	for _, status := range svc.Statuses() {
+		known[status] = true
+	}
+	for status := range result {
+		if !known[status] {
+			return fmt.Errorf("invalid status %q in the handler result, expected one of: %v", status, svc.Statuses())
+		}
+	}
+
+	client, err := s.Client()
+	if err != nil {
+		return err
+	}
+
+	for _, status := range svc.Statuses() {
+		if len(result[status]) == 0 {
+			continue
+		}
+
+		_, records, err := cmdutil.JSONRecords(result[status])
+		if err != nil {
+			return err
+		}
+
+		results, err := cmdutil.RunImport(s.ctx, client, contactImportsPath, newImport(svc, n, status), records, s.SyncFlags, s.KeepDefinition)
+		if err != nil {
+			return fmt.Errorf("failed to respond %s: %w", status, err)
+		}
+
+		rejects := cmdutil.Rejects(results)
+		for _, r := range rejects {
+			s.Log.Printf("%s %s: rejected %s: %s", svc.Name, n.Instance, r.Identifier, r.Message)
+		}
+		s.Log.Printf("%s %s: responded %s for %d of %d contacts", svc.Name, n.Instance, status, len(results)-len(rejects), len(results))
+	}
+	return nil
+}
+
+// newImport returns the import definition responding with status. Feeders
+// import the fields of the record definition, without erasing existing
+// values, other services only match the contacts.
+func newImport(svc *Service, n *Notification, status string) *bulkapi.Import {
+	i := &bulkapi.Import{
+		Name:                fmt.Sprintf("eloquactl serve %s %s %s", svc.Name, n.Instance, status),
+		Fields:              map[string]string{svc.Identifier: svc.RecordDefinition[svc.Identifier]},
+		IdentifierFieldName: svc.Identifier,
+		UpdateRule:          "always",
+	}
+	i.Name = truncate(i.Name, maxNameLength)
+
+	switch svc.Type {
+	case TypeFeeder:
+		i.Fields = svc.RecordDefinition
+		i.UpdateRule = "ifNewIsNotNull"
+		i.SyncActions = []bulkapi.SyncAction{{Destination: bulkapi.FeederDestination(n.Instance), Action: bulkapi.SyncActionAdd}}
+	case TypeDecision:
+		i.SyncActions = []bulkapi.SyncAction{{Destination: bulkapi.DecisionDestination(n.Instance, n.Execution), Action: bulkapi.SyncActionSetStatus, Status: status}}
+	case TypeAction:
+		i.SyncActions = []bulkapi.SyncAction{{Destination: bulkapi.ActionDestination(n.Instance, n.Execution), Action: bulkapi.SyncActionSetStatus, Status: status}}
+	}
+	return i
+}
+
+// truncate shortens s to at most n bytes, on a rune boundary.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
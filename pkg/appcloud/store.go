@@ -0,0 +1,156 @@
+package appcloud
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Instance is a service instance created by Eloqua, e.g. a decision step
+// on a campaign canvas.
+type Instance struct {
+	ID            string     `json:"id"`
+	Service       string     `json:"service"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	ConfiguredAt  *time.Time `json:"configuredAt,omitempty"`
+	NotifiedAt    *time.Time `json:"notifiedAt,omitempty"`
+	Notifications int        `json:"notifications"`
+}
+
+// ErrUnknownInstance is returned by Update for an instance that is not in
+// the store, e.g. one deleted meanwhile.
+var ErrUnknownInstance = errors.New("unknown instance")
+
+// Store keeps the instances in a JSON file.
+type Store struct {
+	Path string
+
+	mu        sync.Mutex
+	instances map[string]*Instance
+}
+
+// OpenStore reads the instances from path. A missing file is an empty store.
+func OpenStore(path string) (*Store, error) {
+	s := &Store{Path: path, instances: map[string]*Instance{}}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &s.instances); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get returns a copy of the instance of the service, nil if there is none.
+func (s *Store) Get(service, id string) *Instance {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, ok := s.instances[key(service, id)]
+	if !ok {
+		return nil
+	}
+	c := *i
+	return &c
+}
+
+// Create adds an instance of the service, unless it is there already, and
+// saves it.
+func (s *Store) Create(service, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.instances[key(service, id)]; !ok {
+		s.instances[key(service, id)] = &Instance{ID: id, Service: service, CreatedAt: time.Now()}
+	}
+	return s.save()
+}
+
+// Update calls fn with the instance of the service and saves it. It returns
+// ErrUnknownInstance when there is no such instance, so an instance deleted
+// meanwhile is not created again.
+func (s *Store) Update(service, id string, fn func(i *Instance)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, ok := s.instances[key(service, id)]
+	if !ok {
+		return ErrUnknownInstance
+	}
+	fn(i)
+	return s.save()
+}
+
+// Delete removes the instance of the service.
+func (s *Store) Delete(service, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.instances, key(service, id))
+	return s.save()
+}
+
+// List returns the instances, sorted by service and creation time.
+func (s *Store) List() []Instance {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]Instance, 0, len(s.instances))
+	for _, i := range s.instances {
+		list = append(list, *i)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Service != list[j].Service {
+			return list[i].Service < list[j].Service
+		}
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list
+}
+
+// save writes the store through a temporary file, so that it is never
+// partly written.
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.instances, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.Path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+func key(service, id string) string {
+	return service + "/" + id
+}
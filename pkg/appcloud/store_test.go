@@ -0,0 +1,48 @@
+package appcloud
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tempStore opens a store in a temporary directory, removed by the returned func.
+func tempStore(t *testing.T) (*Store, func()) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := OpenStore(filepath.Join(dir, "instances.json"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return s, func() { os.RemoveAll(dir) }
+}
+
+func TestStoreUpdateDeleted(t *testing.T) {
+	s, cleanup := tempStore(t)
+	defer cleanup()
+
+	if err := s.Update("offers", "a1", func(i *Instance) {}); err != ErrUnknownInstance {
+		t.Fatalf("Update of a missing instance = %v, want ErrUnknownInstance", err)
+	}
+
+	if err := s.Create("offers", "a1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Update("offers", "a1", func(i *Instance) { i.Notifications++ }); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("offers", "a1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Update("offers", "a1", func(i *Instance) { i.Notifications++ }); err != ErrUnknownInstance {
+		t.Fatalf("Update of a deleted instance = %v, want ErrUnknownInstance", err)
+	}
+	if i := s.Get("offers", "a1"); i != nil {
+		t.Errorf("the deleted instance was created again: %+v", i)
+	}
+}
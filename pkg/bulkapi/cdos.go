@@ -0,0 +1,16 @@
+package bulkapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// UriID returns the id at the end of an uri, e.g. /customObjects/15.
+func UriID(uri string) (int, error) {
+	id, err := strconv.Atoi(uri[strings.LastIndex(uri, "/")+1:])
+	if err != nil {
+		return 0, fmt.Errorf("invalid uri %q", uri)
+	}
+	return id, nil
+}
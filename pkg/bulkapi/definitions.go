@@ -0,0 +1,120 @@
+package bulkapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+)
+
+const (
+	definitionsPageSize = 1000
+)
+
+type ExportList struct {
+	Count        int           `json:"count,omitempty"`
+	HasMore      bool          `json:"hasMore,omitempty"`
+	Items        []bulk.Export `json:"items,omitempty"`
+	Limit        int           `json:"limit,omitempty"`
+	Offset       int           `json:"offset,omitempty"`
+	TotalResults int64         `json:"totalResults,omitempty"`
+}
+
+// ExportsPath returns the path of the export definitions of an entity type:
+// contacts, accounts, activities or a custom object given by its id.
+func ExportsPath(entity string, cdoId int) (string, error) {
+	switch entity {
+	case "contacts", "accounts", "activities":
+		return "/" + entity + "/exports", nil
+	case "cdos", "customObjects":
+		return fmt.Sprintf("/customObjects/%d/exports", cdoId), nil
+	default:
+		return "", fmt.Errorf("unknown entity type %q", entity)
+	}
+}
+
+// ListExports returns the export definitions under path, e.g. /contacts/exports.
+// If q is not empty, only the definitions matching the search are returned, e.g. name='Weekly*'.
+// Eloqua API docs: https://docs.oracle.com/cloud/latest/marketingcs_gs/OMCAC/op-api-bulk-2.0-contacts-exports-get.html
+func ListExports(ctx context.Context, client *bulk.BulkClient, path, q string) ([]bulk.Export, error) {
+	var exports []bulk.Export
+
+	for offset := 0; ; offset += definitionsPageSize {
+		v := url.Values{}
+		v.Set("limit", strconv.Itoa(definitionsPageSize))
+		v.Set("offset", strconv.Itoa(offset))
+		if q != "" {
+			v.Set("q", q)
+		}
+
+		req, err := client.NewRequest("GET", path+"?"+v.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		r := &ExportList{}
+		if _, err := client.Do(ctx, req, r); err != nil {
+			return nil, err
+		}
+
+		exports = append(exports, r.Items...)
+		if !r.HasMore {
+			return exports, nil
+		}
+	}
+}
+
+// GetExport returns the export definition given its uri, e.g. /contacts/exports/123.
+func GetExport(ctx context.Context, client *bulk.BulkClient, uri string) (*bulk.Export, error) {
+	req, err := client.NewRequest("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &bulk.Export{}
+	if _, err := client.Do(ctx, req, r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// FindExport returns the most recent export definition under path with
+// exactly the given name, or nil if there is none.
+func FindExport(ctx context.Context, client *bulk.BulkClient, path, name string) (*bulk.Export, error) {
+	var q string
+	// the search syntax has no escaping, fall back to listing all definitions
+	if !strings.ContainsAny(name, "'*") {
+		q = fmt.Sprintf("name='%s'", name)
+	}
+
+	exports, err := ListExports(ctx, client, path, q)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *bulk.Export
+	for i := range exports {
+		e := &exports[i]
+		if e.Name == name && (found == nil || e.CreatedAt > found.CreatedAt) {
+			found = e
+		}
+	}
+	return found, nil
+}
+
+// DeleteDefinition deletes an export or import definition given its uri,
+// e.g. /contacts/exports/123.
+func DeleteDefinition(ctx context.Context, client *bulk.BulkClient, uri string) error {
+	req, err := client.NewRequest("DELETE", uri, nil)
+	if err != nil {
+		return err
+	}
+
+	var v interface{}
+	_, err = client.Do(ctx, req, &v)
+	return err
+}
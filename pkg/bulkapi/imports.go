@@ -0,0 +1,127 @@
+package bulkapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+)
+
+// Import sync actions and the statuses set by them.
+const (
+	SyncActionAdd       = "add"
+	SyncActionSetStatus = "setStatus"
+
+	StatusYes      = "yes"
+	StatusNo       = "no"
+	StatusComplete = "complete"
+	StatusErrored  = "errored"
+)
+
+// SyncAction is an action taken on the imported records, e.g. setting the
+// status of the records in an App Cloud service instance.
+type SyncAction struct {
+	Destination string `json:"destination,omitempty"`
+	Action      string `json:"action"`
+	Status      string `json:"status,omitempty"`
+}
+
+// Import is an import definition. eloqua-go has no imports.
+type Import struct {
+	Uri                              string            `json:"uri,omitempty"`
+	Name                             string            `json:"name"`
+	Fields                           map[string]string `json:"fields"`
+	IdentifierFieldName              string            `json:"identifierFieldName,omitempty"`
+	IsSyncTriggeredOnImport          bool              `json:"isSyncTriggeredOnImport"`
+	IsUpdatingMultipleMatchedRecords bool              `json:"isUpdatingMultipleMatchedRecords,omitempty"`
+	NullIdentifierFieldName          bool              `json:"nullIdentifierFieldName,omitempty"`
+	SyncActions                      []SyncAction      `json:"syncActions,omitempty"`
+	UpdateRule                       string            `json:"updateRule,omitempty"`
+	AutoDeleteDuration               string            `json:"autoDeleteDuration,omitempty"`
+	DataRetentionDuration            string            `json:"dataRetentionDuration,omitempty"`
+	CreatedAt                        string            `json:"createdAt,omitempty"`
+	CreatedBy                        string            `json:"createdBy,omitempty"`
+}
+
+// ImportResult is the outcome of importing a record.
+type ImportResult struct {
+	Row        int    `json:"row"`
+	Identifier string `json:"identifier"`
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+}
+
+// Import result statuses.
+const (
+	ResultImported = "imported"
+	ResultRejected = "rejected"
+)
+
+// InstanceID returns the id of an App Cloud service instance as markup
+// expects it: Eloqua calls services with the id as a GUID, e.g.
+// a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f, while markup uses it without dashes.
+func InstanceID(id string) string {
+	return strings.Replace(id, "-", "", -1)
+}
+
+// FeederDestination returns the sync action destination that adds contacts
+// to the campaign of a feeder service instance.
+func FeederDestination(instance string) string {
+	return fmt.Sprintf("{{FeederInstance(%s)}}", InstanceID(instance))
+}
+
+// DecisionDestination returns the sync action destination of the contacts
+// waiting in a decision service instance, in a single execution if it is
+// not zero.
+func DecisionDestination(instance string, execution int) string {
+	if execution > 0 {
+		return fmt.Sprintf("{{DecisionInstance(%s).Execution[%d]}}", InstanceID(instance), execution)
+	}
+	return fmt.Sprintf("{{DecisionInstance(%s)}}", InstanceID(instance))
+}
+
+// ActionDestination returns the sync action destination of the contacts
+// waiting in an action service instance, in a single execution if it is
+// not zero. It also selects the contacts in export filters.
+func ActionDestination(instance string, execution int) string {
+	if execution > 0 {
+		return fmt.Sprintf("{{ActionInstance(%s).Execution[%d]}}", InstanceID(instance), execution)
+	}
+	return fmt.Sprintf("{{ActionInstance(%s)}}", InstanceID(instance))
+}
+
+// ContentField returns the field statement of the content of the contacts
+// in an execution of a content service instance, the HTML shown to them.
+func ContentField(instance string, execution int) string {
+	return fmt.Sprintf("{{ContentInstance(%s).Execution[%d]}}", InstanceID(instance), execution)
+}
+
+// CreateImport creates the import definition under path, e.g. /contacts/imports.
+// Eloqua API docs: https://docs.oracle.com/cloud/latest/marketingcs_gs/OMCAC/op-api-bulk-2.0-contacts-imports-post.html
+func CreateImport(ctx context.Context, client *bulk.BulkClient, path string, i *Import) (*Import, error) {
+	req, err := client.NewRequest("POST", path, i)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Import{}
+	if _, err := client.Do(ctx, req, r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// UploadData uploads records to the staging area of the import definition
+// given by its uri. Records are keyed by the field names of the definition.
+func UploadData(ctx context.Context, client *bulk.BulkClient, uri string, records []map[string]string) error {
+	req, err := client.NewRequest("POST", uri+"/data", records)
+	if err != nil {
+		return err
+	}
+
+	var v interface{}
+	_, err = client.Do(ctx, req, &v)
+	return err
+}
@@ -0,0 +1,29 @@
+package bulkapi
+
+import "testing"
+
+func TestDestinations(t *testing.T) {
+	const guid = "a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f"
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"feeder", FeederDestination(guid), "{{FeederInstance(a12d53dd1a2b4c5d8e9f0a1b2c3d4e5f)}}"},
+		{"decision", DecisionDestination(guid, 0), "{{DecisionInstance(a12d53dd1a2b4c5d8e9f0a1b2c3d4e5f)}}"},
+		{"decision execution", DecisionDestination(guid, 7), "{{DecisionInstance(a12d53dd1a2b4c5d8e9f0a1b2c3d4e5f).Execution[7]}}"},
+		{"action", ActionDestination(guid, 0), "{{ActionInstance(a12d53dd1a2b4c5d8e9f0a1b2c3d4e5f)}}"},
+		{"action execution", ActionDestination(guid, 7), "{{ActionInstance(a12d53dd1a2b4c5d8e9f0a1b2c3d4e5f).Execution[7]}}"},
+		{"content", ContentField(guid, 7), "{{ContentInstance(a12d53dd1a2b4c5d8e9f0a1b2c3d4e5f).Execution[7]}}"},
+		{"without dashes", FeederDestination("a12d53dd1a2b4c5d8e9f0a1b2c3d4e5f"), "{{FeederInstance(a12d53dd1a2b4c5d8e9f0a1b2c3d4e5f)}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %s, want %s", tt.got, tt.want)
+			}
+		})
+	}
+}
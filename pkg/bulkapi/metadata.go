@@ -0,0 +1,90 @@
+package bulkapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/cache"
+)
+
+// Metadata lists the fields, custom objects and lead scoring models of the
+// instance, through the cache if it is not nil.
+type Metadata struct {
+	Client *bulk.BulkClient
+	Cache  *cache.Cache
+}
+
+func (m *Metadata) ContactFields(ctx context.Context) (*bulk.ContactFieldSearchResponse, error) {
+	var r *bulk.ContactFieldSearchResponse
+	err := m.Cache.Fetch("contacts/fields", &r, func() (err error) {
+		r, err = m.Client.Contacts.GetFields(ctx)
+		return err
+	})
+	return r, err
+}
+
+func (m *Metadata) AccountFields(ctx context.Context) (*bulk.AccountFieldSearchResponse, error) {
+	var r *bulk.AccountFieldSearchResponse
+	err := m.Cache.Fetch("accounts/fields", &r, func() (err error) {
+		r, err = m.Client.Accounts.GetFields(ctx)
+		return err
+	})
+	return r, err
+}
+
+func (m *Metadata) ActivityFields(ctx context.Context, activityType string) (*bulk.ActivityFieldSearchResponse, error) {
+	var r *bulk.ActivityFieldSearchResponse
+	err := m.Cache.Fetch("activities/"+activityType+"/fields", &r, func() (err error) {
+		r, err = m.Client.Activities.ListFields(ctx, &bulk.ActivityFieldListQueryOptions{ActivityType: activityType})
+		return err
+	})
+	return r, err
+}
+
+func (m *Metadata) CdoFields(ctx context.Context, id int) (*bulk.CdoFieldSearchResponse, error) {
+	var r *bulk.CdoFieldSearchResponse
+	err := m.Cache.Fetch(fmt.Sprintf("cdos/%d/fields", id), &r, func() (err error) {
+		r, err = m.Client.Cdos.ListFields(ctx, id)
+		return err
+	})
+	return r, err
+}
+
+func (m *Metadata) Cdos(ctx context.Context) (*bulk.CdoSearchResponse, error) {
+	var r *bulk.CdoSearchResponse
+	err := m.Cache.Fetch("cdos", &r, func() (err error) {
+		r, err = m.Client.Cdos.List(ctx)
+		return err
+	})
+	return r, err
+}
+
+func (m *Metadata) LeadModels(ctx context.Context) (*bulk.LeadModelsSearchResponse, error) {
+	var r *bulk.LeadModelsSearchResponse
+	err := m.Cache.Fetch("contacts/leadmodels", &r, func() (err error) {
+		r, err = m.Client.Contacts.ListLeadModels(ctx)
+		return err
+	})
+	return r, err
+}
+
+// CdoID returns the id of the custom object given its name or id.
+func (m *Metadata) CdoID(ctx context.Context, nameOrID string) (int, error) {
+	if id, err := strconv.Atoi(nameOrID); err == nil {
+		return id, nil
+	}
+
+	cdos, err := m.Cdos(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, cdo := range cdos.Items {
+		if cdo.Name == nameOrID {
+			return UriID(cdo.Uri)
+		}
+	}
+	return 0, fmt.Errorf("custom object %q not found", nameOrID)
+}
@@ -0,0 +1,186 @@
+// Package bulkapi implements the Bulk API endpoints that are missing from
+// the eloqua-go bulk client, on top of the client's NewRequest and Do.
+package bulkapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+)
+
+const (
+	logsPageSize    = 1000
+	rejectsPageSize = 1000
+	syncsPageSize   = 1000
+)
+
+// Sync statuses, see https://docs.oracle.com/cloud/latest/marketingcs_gs/OMCAC/op-api-bulk-2.0-syncs-id-get.html
+const (
+	SyncPending = "pending"
+	SyncActive  = "active"
+	SyncSuccess = "success"
+	SyncWarning = "warning"
+	SyncError   = "error"
+)
+
+// SyncLog is a sync log entry. The eloqua-go type misspells the severity field.
+type SyncLog struct {
+	SyncURI    string `json:"syncUri"`
+	Count      int    `json:"count"`
+	Severity   string `json:"severity"`
+	StatusCode string `json:"statusCode"`
+	Message    string `json:"message"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+type SyncLogList struct {
+	Count        int       `json:"count,omitempty"`
+	HasMore      bool      `json:"hasMore,omitempty"`
+	Items        []SyncLog `json:"items,omitempty"`
+	Limit        int       `json:"limit,omitempty"`
+	Offset       int       `json:"offset,omitempty"`
+	TotalResults int64     `json:"totalResults,omitempty"`
+}
+
+// SyncReject is a record rejected by an import sync.
+type SyncReject struct {
+	SyncURI       string            `json:"syncUri"`
+	RecordIndex   int               `json:"recordIndex"`
+	StatusCode    string            `json:"statusCode"`
+	Message       string            `json:"message"`
+	InvalidFields []string          `json:"invalidFields"`
+	FieldValues   map[string]string `json:"fieldValues"`
+}
+
+type SyncRejectList struct {
+	Count        int          `json:"count,omitempty"`
+	HasMore      bool         `json:"hasMore,omitempty"`
+	Items        []SyncReject `json:"items,omitempty"`
+	Limit        int          `json:"limit,omitempty"`
+	Offset       int          `json:"offset,omitempty"`
+	TotalResults int64        `json:"totalResults,omitempty"`
+}
+
+type SyncList struct {
+	Count        int         `json:"count,omitempty"`
+	HasMore      bool        `json:"hasMore,omitempty"`
+	Items        []bulk.Sync `json:"items,omitempty"`
+	Limit        int         `json:"limit,omitempty"`
+	Offset       int         `json:"offset,omitempty"`
+	TotalResults int64       `json:"totalResults,omitempty"`
+}
+
+// IsDone reports whether the sync reached a final status.
+func IsDone(status string) bool {
+	return status == SyncSuccess || status == SyncWarning || status == SyncError
+}
+
+// SyncID returns the id of the sync given its uri, e.g. /syncs/123.
+func SyncID(uri string) (int, error) {
+	return UriID(uri)
+}
+
+// SyncLogs returns all log entries of the sync.
+// Eloqua API docs: https://docs.oracle.com/cloud/latest/marketingcs_gs/OMCAC/op-api-bulk-2.0-syncs-id-logs-get.html
+func SyncLogs(ctx context.Context, client *bulk.BulkClient, id int) ([]SyncLog, error) {
+	var logs []SyncLog
+
+	for offset := 0; ; offset += logsPageSize {
+		u := fmt.Sprintf("/syncs/%v/logs?limit=%v&offset=%v", id, logsPageSize, offset)
+		req, err := client.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		r := &SyncLogList{}
+		if _, err := client.Do(ctx, req, r); err != nil {
+			return nil, err
+		}
+
+		logs = append(logs, r.Items...)
+		if !r.HasMore {
+			return logs, nil
+		}
+	}
+}
+
+// SyncRejects returns all records rejected by the sync.
+// Eloqua API docs: https://docs.oracle.com/cloud/latest/marketingcs_gs/OMCAC/op-api-bulk-2.0-syncs-id-rejects-get.html
+func SyncRejects(ctx context.Context, client *bulk.BulkClient, id int) ([]SyncReject, error) {
+	var rejects []SyncReject
+
+	for offset := 0; ; offset += rejectsPageSize {
+		u := fmt.Sprintf("/syncs/%v/rejects?limit=%v&offset=%v", id, rejectsPageSize, offset)
+		req, err := client.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		r := &SyncRejectList{}
+		if _, err := client.Do(ctx, req, r); err != nil {
+			return nil, err
+		}
+
+		rejects = append(rejects, r.Items...)
+		if !r.HasMore {
+			return rejects, nil
+		}
+	}
+}
+
+// ListSyncs returns up to limit syncs, the most recent first.
+// If q is not empty, only the syncs matching the search are returned, e.g. status=error.
+// Eloqua API docs: https://docs.oracle.com/cloud/latest/marketingcs_gs/OMCAC/op-api-bulk-2.0-syncs-get.html
+func ListSyncs(ctx context.Context, client *bulk.BulkClient, q string, limit int) ([]bulk.Sync, error) {
+	var syncs []bulk.Sync
+
+	for offset := 0; len(syncs) < limit; offset += syncsPageSize {
+		v := url.Values{}
+		v.Set("limit", strconv.Itoa(syncsPageSize))
+		v.Set("offset", strconv.Itoa(offset))
+		v.Set("orderBy", "createdAt DESC")
+		if q != "" {
+			v.Set("q", q)
+		}
+
+		req, err := client.NewRequest("GET", "/syncs?"+v.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		r := &SyncList{}
+		if _, err := client.Do(ctx, req, r); err != nil {
+			return nil, err
+		}
+
+		syncs = append(syncs, r.Items...)
+		if !r.HasMore {
+			break
+		}
+	}
+
+	if len(syncs) > limit {
+		syncs = syncs[:limit]
+	}
+	return syncs, nil
+}
+
+// GetDefinition returns an export or import definition as it is returned
+// by the API, given its uri, e.g. /contacts/imports/123.
+func GetDefinition(ctx context.Context, client *bulk.BulkClient, uri string) (json.RawMessage, error) {
+	req, err := client.NewRequest("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var r json.RawMessage
+	if _, err := client.Do(ctx, req, &r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
@@ -0,0 +1,196 @@
+// Package cache keeps instance metadata that rarely changes, e.g. the fields
+// of contacts or the ids of custom objects, in a file per context, so that
+// commands do not list them on every run.
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTTL = 24 * time.Hour
+)
+
+type entry struct {
+	StoredAt time.Time       `json:"storedAt"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// Entry describes a cached value.
+type Entry struct {
+	Key      string    `json:"key"`
+	StoredAt time.Time `json:"storedAt"`
+	Expired  bool      `json:"expired"`
+	Size     int       `json:"size"`
+}
+
+// Cache is a file of values stored under keys, e.g. cdos/15/fields.
+// A nil *Cache caches nothing.
+type Cache struct {
+	Path string
+	TTL  time.Duration
+	// Refresh makes the first Fetch of every key call the API.
+	Refresh bool
+
+	// mu guards the fields below and the file, keys are locked while their
+	// value is fetched, so the same value is not fetched twice at once
+	mu        sync.Mutex
+	refreshed map[string]bool
+	keys      map[string]*sync.Mutex
+}
+
+func New(path string, ttl time.Duration, refresh bool) *Cache {
+	return &Cache{
+		Path:      path,
+		TTL:       ttl,
+		Refresh:   refresh,
+		refreshed: map[string]bool{},
+		keys:      map[string]*sync.Mutex{},
+	}
+}
+
+// Fetch fills v with the value cached under key. When the value is missing,
+// expired or to be refreshed, fetch is called to fill v and v is cached.
+func (c *Cache) Fetch(key string, v interface{}, fetch func() error) error {
+	if c == nil {
+		return fetch()
+	}
+
+	// other keys are fetched meanwhile, the same key waits for the value
+	kl := c.keyLock(key)
+	kl.Lock()
+	defer kl.Unlock()
+
+	if c.cached(key, v) {
+		return nil
+	}
+
+	if err := fetch(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshed[key] = true
+
+	// other processes may have cached other keys meanwhile
+	entries := c.load()
+	entries[key] = entry{StoredAt: time.Now(), Value: data}
+	if err := c.save(entries); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write metadata cache %s: %v\n", c.Path, err)
+	}
+	return nil
+}
+
+// keyLock returns the lock of key.
+func (c *Cache) keyLock(key string) *sync.Mutex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.keys == nil {
+		c.keys = map[string]*sync.Mutex{}
+	}
+	kl, ok := c.keys[key]
+	if !ok {
+		kl = &sync.Mutex{}
+		c.keys[key] = kl
+	}
+	return kl
+}
+
+// cached fills v with the value cached under key and reports whether it is
+// fresh, not expired and refreshed already if it is to be.
+func (c *Cache) cached(key string, v interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.load()[key]
+	if !ok || time.Since(e.StoredAt) >= c.TTL || c.Refresh && !c.refreshed[key] {
+		return false
+	}
+	return json.Unmarshal(e.Value, v) == nil
+}
+
+// Entries returns the cached values, sorted by key.
+func (c *Cache) Entries() []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var list []Entry
+	for key, e := range c.load() {
+		list = append(list, Entry{Key: key, StoredAt: e.StoredAt, Expired: time.Since(e.StoredAt) >= c.TTL, Size: len(e.Value)})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list
+}
+
+// Clear removes the cached values.
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.Remove(c.Path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// load reads the cache file. A missing or broken file is an empty cache.
+func (c *Cache) load() map[string]entry {
+	entries := map[string]entry{}
+
+	data, err := ioutil.ReadFile(c.Path)
+	if err != nil {
+		return entries
+	}
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return map[string]entry{}
+	}
+	return entries
+}
+
+// save writes the cache file through a temporary file, so that readers never
+// see a partly written cache.
+func (c *Cache) save(entries map[string]entry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(c.Path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, filepath.Base(c.Path)+".*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.Path)
+}
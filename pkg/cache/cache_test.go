@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tempCache returns a cache in a temporary directory, removed by the returned func.
+func tempCache(t *testing.T) (*Cache, func()) {
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(filepath.Join(dir, "cache.json"), DefaultTTL, false), func() { os.RemoveAll(dir) }
+}
+
+func TestFetchCaches(t *testing.T) {
+	c, cleanup := tempCache(t)
+	defer cleanup()
+
+	var calls int32
+	fetch := func(v *[]string) func() error {
+		return func() error {
+			atomic.AddInt32(&calls, 1)
+			*v = []string{"C_EmailAddress"}
+			return nil
+		}
+	}
+
+	var first, second []string
+	if err := c.Fetch("contacts/fields", &first, fetch(&first)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Fetch("contacts/fields", &second, fetch(&second)); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 1 {
+		t.Errorf("fetched %d times, want 1", calls)
+	}
+	if len(second) != 1 || second[0] != "C_EmailAddress" {
+		t.Errorf("cached value = %v", second)
+	}
+}
+
+func TestFetchKeysConcurrently(t *testing.T) {
+	c, cleanup := tempCache(t)
+	defer cleanup()
+
+	// each fetch waits for the fetch of the other key to start, which
+	// it can only when the keys are fetched at the same time
+	started := map[string]chan struct{}{"a": make(chan struct{}), "b": make(chan struct{})}
+	other := map[string]string{"a": "b", "b": "a"}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for key := range started {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			var v string
+			errs <- c.Fetch(key, &v, func() error {
+				close(started[key])
+				<-started[other[key]]
+				v = key
+				return nil
+			})
+		}(key)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the fetch of a key waited for the fetch of another key")
+	}
+
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestFetchSameKeyOnce(t *testing.T) {
+	c, cleanup := tempCache(t)
+	defer cleanup()
+
+	var calls int32
+	var wg sync.WaitGroup
+	for n := 0; n < 10; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var v int
+			err := c.Fetch("cdos/ids", &v, func() error {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(10 * time.Millisecond)
+				v = 42
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+			if v != 42 {
+				t.Errorf("value = %d, want 42", v)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("fetched %d times, want 1", calls)
+	}
+}
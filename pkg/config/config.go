@@ -0,0 +1,366 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultFileName is the name of the config file looked up in the home directory.
+	DefaultFileName = ".eloquactl.yaml"
+
+	// legacyContextName is the name given to the context built from the
+	// single instance 'auth', 'bulkUrl' and 'restUrl' keys.
+	legacyContextName = "default"
+)
+
+var (
+	// path of the config file, set by the root command
+	path string
+
+	ErrNoContext = errors.New("no context is set, use 'eloquactl config use-context NAME' or the --context flag")
+)
+
+// Config represents eloquactl config file. It holds a number of named
+// contexts, each pointing to an Eloqua instance, and the credentials they refer to.
+type Config struct {
+	CurrentContext string            `yaml:"current-context,omitempty"`
+	Contexts       []NamedContext    `yaml:"contexts,omitempty"`
+	Credentials    []NamedCredential `yaml:"credentials,omitempty"`
+
+	// FieldSets are named export fields, selected with --field-set.
+	FieldSets []FieldSet `yaml:"fieldSets,omitempty"`
+
+	// Single instance configuration, used when no contexts are defined.
+	Auth    map[string]string `yaml:"auth,omitempty"`
+	BulkURL string            `yaml:"bulkUrl,omitempty"`
+	RestURL string            `yaml:"restUrl,omitempty"`
+
+	// Extra keeps the keys eloquactl does not know, e.g. ones read by viper,
+	// so that Save writes them back.
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// FieldSet is a named set of export fields of an entity type: contacts,
+// accounts, activities, cdos or scores.
+type FieldSet struct {
+	Name   string            `yaml:"name"`
+	Entity string            `yaml:"entity"`
+	Fields map[string]string `yaml:"fields"`
+}
+
+type NamedContext struct {
+	Name    string  `yaml:"name"`
+	Context Context `yaml:"context"`
+}
+
+// Context describes an Eloqua instance and the user to access it with.
+type Context struct {
+	// Name of the context, filled in when the context is looked up.
+	Name string `yaml:"-"`
+
+	Company string `yaml:"company"`
+	User    string `yaml:"user"`
+
+	// Name of the credentials entry used to authenticate the user.
+	Credentials string `yaml:"credentials,omitempty"`
+
+	// BaseURL is the pod specific URL of the instance, e.g. https://secure.p03.eloqua.com
+	BaseURL string `yaml:"baseUrl,omitempty"`
+
+	// Base URLs of the Bulk and REST APIs, may contain a {version} placeholder.
+	BulkURL string `yaml:"bulkUrl,omitempty"`
+	RestURL string `yaml:"restUrl,omitempty"`
+}
+
+type NamedCredential struct {
+	Name       string     `yaml:"name"`
+	Credential Credential `yaml:"credential"`
+}
+
+const (
+	AuthProviderBasic  = "basic"
+	AuthProviderOAuth2 = "oauth2"
+
+	GrantPassword          = "password"
+	GrantAuthorizationCode = "authorization_code"
+)
+
+// Credential holds the secrets of a context user.
+type Credential struct {
+	// AuthProvider is either 'basic' (default) or 'oauth2'.
+	AuthProvider string `yaml:"auth-provider,omitempty"`
+
+	// Password kept in plain text, prefer Source.
+	Password string `yaml:"password,omitempty"`
+
+	OAuth2 *OAuth2 `yaml:"oauth2,omitempty"`
+
+	// Source tells where the password and the OAuth2 client secret are kept.
+	Source *CredentialSource `yaml:"source,omitempty"`
+}
+
+const (
+	SourceInline  = "inline"
+	SourceEnv     = "env"
+	SourceKeyring = "keyring"
+	SourceFile    = "file"
+	SourceExec    = "exec"
+)
+
+// CredentialSource describes where the secrets of a credentials entry are kept.
+type CredentialSource struct {
+	// Type is one of: inline|env|keyring|file|exec.
+	Type string `yaml:"type"`
+
+	// Environment variables holding the secrets, used by the env source.
+	PasswordEnv     string `yaml:"password-env,omitempty"`
+	ClientSecretEnv string `yaml:"client-secret-env,omitempty"`
+
+	// Path of the encrypted file, used by the file source.
+	Path string `yaml:"path,omitempty"`
+
+	// Credential helper command, used by the exec source.
+	Command string            `yaml:"command,omitempty"`
+	Args    []string          `yaml:"args,omitempty"`
+	Env     map[string]string `yaml:"env,omitempty"`
+}
+
+// OAuth2 describes the App Cloud app used to obtain OAuth2 tokens.
+type OAuth2 struct {
+	ClientID     string `yaml:"client-id"`
+	ClientSecret string `yaml:"client-secret,omitempty"`
+	RedirectURL  string `yaml:"redirect-url,omitempty"`
+
+	// Authorization and token endpoints, Eloqua's ones if empty.
+	AuthURL  string `yaml:"auth-url,omitempty"`
+	TokenURL string `yaml:"token-url,omitempty"`
+
+	// Grant is either 'authorization_code' (default) or 'password'.
+	Grant string `yaml:"grant,omitempty"`
+}
+
+// SetPath sets the config file path. Empty path resets it to the default one.
+func SetPath(p string) {
+	path = p
+}
+
+// Path returns the path of the config file.
+func Path() (string, error) {
+	if path != "" {
+		return path, nil
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, DefaultFileName), nil
+}
+
+// Dir returns the directory where eloquactl keeps its state, e.g. cached tokens.
+func Dir() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".eloquactl"), nil
+}
+
+// Load reads the config file. Missing config file results in an empty config.
+func Load() (*Config, error) {
+	p, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Config{}, nil
+		}
+		return nil, err
+	}
+
+	c := &Config{}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed parsing config file %s: %v", p, err)
+	}
+
+	c.migrateLegacy()
+	return c, nil
+}
+
+// Save writes the config to the config file.
+func (c *Config) Save() error {
+	p, err := Path()
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(p, data, 0600)
+}
+
+// migrateLegacy turns the single instance configuration into a named context.
+func (c *Config) migrateLegacy() {
+	if len(c.Contexts) > 0 || c.Auth == nil {
+		return
+	}
+
+	c.SetContext(legacyContextName, Context{
+		Company:     c.Auth["company"],
+		User:        c.Auth["username"],
+		Credentials: legacyContextName,
+		BulkURL:     c.BulkURL,
+		RestURL:     c.RestURL,
+	})
+	c.SetCredential(legacyContextName, Credential{Password: c.Auth["password"]})
+
+	if c.CurrentContext == "" {
+		c.CurrentContext = legacyContextName
+	}
+
+	c.Auth = nil
+	c.BulkURL = ""
+	c.RestURL = ""
+}
+
+// Context returns the context with the given name.
+// If name is empty, the current context is returned.
+func (c *Config) Context(name string) (*Context, error) {
+	if name == "" {
+		name = c.CurrentContext
+	}
+
+	if name == "" {
+		return nil, ErrNoContext
+	}
+
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == name {
+			ctx := &c.Contexts[i].Context
+			ctx.Name = name
+			return ctx, nil
+		}
+	}
+
+	return nil, fmt.Errorf("context %q does not exist", name)
+}
+
+// SetContext adds a new context or replaces an existing one.
+func (c *Config) SetContext(name string, ctx Context) {
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == name {
+			c.Contexts[i].Context = ctx
+			return
+		}
+	}
+	c.Contexts = append(c.Contexts, NamedContext{Name: name, Context: ctx})
+}
+
+// Credential returns the credentials entry with the given name.
+func (c *Config) Credential(name string) (*Credential, error) {
+	for i := range c.Credentials {
+		if c.Credentials[i].Name == name {
+			return &c.Credentials[i].Credential, nil
+		}
+	}
+
+	return nil, fmt.Errorf("credentials %q do not exist", name)
+}
+
+// SetCredential adds a new credentials entry or replaces an existing one.
+func (c *Config) SetCredential(name string, cred Credential) {
+	for i := range c.Credentials {
+		if c.Credentials[i].Name == name {
+			c.Credentials[i].Credential = cred
+			return
+		}
+	}
+	c.Credentials = append(c.Credentials, NamedCredential{Name: name, Credential: cred})
+}
+
+// BulkEndpoint returns the Bulk API base URL for the given API version.
+func (c *Context) BulkEndpoint(version string) string {
+	return strings.Replace(c.BulkURL, "{version}", version, 1)
+}
+
+// RestEndpoint returns the REST API base URL for the given API version.
+func (c *Context) RestEndpoint(version string) string {
+	return strings.Replace(c.RestURL, "{version}", version, 1)
+}
+
+// Username returns the user name in the form Eloqua expects it, i.e. 'company\user'.
+func (c *Context) Username() string {
+	return fmt.Sprintf("%v\\%v", c.Company, c.User)
+}
+
+// Resolve loads the config file and returns the context with the given name
+// together with its credentials. If name is empty, the current context is used.
+func Resolve(name string) (*Context, *Credential, error) {
+	c, err := Load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, err := c.Context(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cred := &Credential{}
+	if ctx.Credentials != "" {
+		cred, err = c.Credential(ctx.Credentials)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return ctx, cred, nil
+}
+
+// MetadataCachePath returns the path of the file caching the metadata of the
+// instance of the given context, e.g. its contact fields.
+func MetadataCachePath(contextName string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "cache", contextName+".json"), nil
+}
+
+// InstanceStorePath returns the path of the file keeping the App Cloud
+// service instances hosted by eloquactl serve.
+func InstanceStorePath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "instances.json"), nil
+}
+
+// TokenCachePath returns the path of the file caching OAuth2 tokens of the given context.
+func TokenCachePath(contextName string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "tokens", contextName+".json"), nil
+}
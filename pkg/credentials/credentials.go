@@ -0,0 +1,144 @@
+// Package credentials resolves the secrets of a credentials entry from the
+// place they are kept: the config file, environment variables, the OS keyring,
+// an encrypted file or an external credential helper.
+package credentials
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/elqx/eloquactl/pkg/config"
+)
+
+const (
+	DefaultPasswordEnv     = "ELOQUACTL_PASSWORD"
+	DefaultClientSecretEnv = "ELOQUACTL_CLIENT_SECRET"
+)
+
+// ErrNotFound is returned by a source that has no secrets for the credentials.
+var ErrNotFound = errors.New("credentials not found")
+
+// Secrets are the sensitive parts of a credentials entry.
+type Secrets struct {
+	Password     string `json:"password,omitempty"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+}
+
+// Source provides the secrets of a named credentials entry.
+type Source interface {
+	Get(name string) (*Secrets, error)
+}
+
+// Storer is implemented by sources that can save secrets.
+type Storer interface {
+	Store(name string, s *Secrets) error
+}
+
+// NewSource returns the source described by src. Nil src is the inline source.
+func NewSource(src *config.CredentialSource, cred *config.Credential) (Source, error) {
+	if src == nil {
+		return &Inline{Credential: cred}, nil
+	}
+
+	switch src.Type {
+	case "", config.SourceInline:
+		return &Inline{Credential: cred}, nil
+	case config.SourceEnv:
+		return &Env{PasswordEnv: src.PasswordEnv, ClientSecretEnv: src.ClientSecretEnv}, nil
+	case config.SourceKeyring:
+		return &Keyring{}, nil
+	case config.SourceFile:
+		if src.Path == "" {
+			return nil, errors.New("file credential source requires a path")
+		}
+		return &File{Path: src.Path}, nil
+	case config.SourceExec:
+		if src.Command == "" {
+			return nil, errors.New("exec credential source requires a command")
+		}
+		return &Exec{Command: src.Command, Args: src.Args, Env: src.Env}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential source %q", src.Type)
+	}
+}
+
+// Fill resolves the secrets of the named credentials entry and sets them on cred.
+// It fails with a descriptive error if a secret required by the auth provider is missing.
+func Fill(name string, cred *config.Credential) error {
+	src, err := NewSource(cred.Source, cred)
+	if err != nil {
+		return fmt.Errorf("credentials %q: %v", name, err)
+	}
+
+	s, err := src.Get(name)
+	if err != nil && err != ErrNotFound {
+		return fmt.Errorf("credentials %q: %v", name, err)
+	}
+	if s == nil {
+		s = &Secrets{}
+	}
+
+	if s.Password != "" {
+		cred.Password = s.Password
+	}
+	if cred.OAuth2 != nil && s.ClientSecret != "" {
+		cred.OAuth2.ClientSecret = s.ClientSecret
+	}
+
+	return validate(name, cred)
+}
+
+// validate checks that secrets required by the auth provider are present.
+func validate(name string, cred *config.Credential) error {
+	source := config.SourceInline
+	if cred.Source != nil && cred.Source.Type != "" {
+		source = cred.Source.Type
+	}
+
+	oauth2 := cred.AuthProvider == config.AuthProviderOAuth2
+	if (!oauth2 || cred.OAuth2 != nil && cred.OAuth2.Grant == config.GrantPassword) && cred.Password == "" {
+		return fmt.Errorf("no password found for credentials %q in the %s source, see 'eloquactl config set-credentials -h'", name, source)
+	}
+
+	if oauth2 && cred.OAuth2 != nil && cred.OAuth2.ClientSecret == "" {
+		return fmt.Errorf("no OAuth2 client secret found for credentials %q in the %s source, see 'eloquactl config set-credentials -h'", name, source)
+	}
+
+	return nil
+}
+
+// Inline reads the secrets from the config file.
+type Inline struct {
+	Credential *config.Credential
+}
+
+func (s *Inline) Get(name string) (*Secrets, error) {
+	r := &Secrets{Password: s.Credential.Password}
+	if s.Credential.OAuth2 != nil {
+		r.ClientSecret = s.Credential.OAuth2.ClientSecret
+	}
+	return r, nil
+}
+
+func (s *Inline) Store(name string, secrets *Secrets) error {
+	s.Credential.Password = secrets.Password
+	if s.Credential.OAuth2 != nil {
+		s.Credential.OAuth2.ClientSecret = secrets.ClientSecret
+	}
+	return nil
+}
+
+// Store saves the secrets to the source of the credentials entry.
+// Inline secrets are set on cred and saved with the config file.
+func Store(name string, cred *config.Credential, s *Secrets) error {
+	src, err := NewSource(cred.Source, cred)
+	if err != nil {
+		return err
+	}
+
+	storer, ok := src.(Storer)
+	if !ok {
+		return fmt.Errorf("secrets can not be saved to the %s source, set them there directly", cred.Source.Type)
+	}
+	return storer.Store(name, s)
+}
@@ -0,0 +1,146 @@
+package credentials
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/elqx/eloquactl/pkg/config"
+)
+
+// setEnv sets an environment variable and returns a func restoring it.
+func setEnv(key, value string) func() {
+	old, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	return func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func TestNewSource(t *testing.T) {
+	cred := &config.Credential{}
+
+	tests := []struct {
+		name    string
+		src     *config.CredentialSource
+		want    Source
+		wantErr bool
+	}{
+		{name: "nil", src: nil, want: &Inline{Credential: cred}},
+		{name: "empty type", src: &config.CredentialSource{}, want: &Inline{Credential: cred}},
+		{name: "env", src: &config.CredentialSource{Type: "env", PasswordEnv: "PW"}, want: &Env{PasswordEnv: "PW"}},
+		{name: "keyring", src: &config.CredentialSource{Type: "keyring"}, want: &Keyring{}},
+		{name: "file", src: &config.CredentialSource{Type: "file", Path: "/tmp/x"}, want: &File{Path: "/tmp/x"}},
+		{name: "file without path", src: &config.CredentialSource{Type: "file"}, wantErr: true},
+		{name: "exec", src: &config.CredentialSource{Type: "exec", Command: "helper"}, want: &Exec{Command: "helper"}},
+		{name: "exec without command", src: &config.CredentialSource{Type: "exec"}, wantErr: true},
+		{name: "unknown", src: &config.CredentialSource{Type: "vault"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSource(tt.src, cred)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("NewSource() = %T, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewSource() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFill(t *testing.T) {
+	defer setEnv("TEST_ELOQUACTL_PW", "from-env")()
+
+	tests := []struct {
+		name         string
+		cred         config.Credential
+		wantPassword string
+		wantSecret   string
+		wantErr      bool
+	}{
+		{
+			name:         "inline password",
+			cred:         config.Credential{Password: "inline"},
+			wantPassword: "inline",
+		},
+		{
+			name:    "missing password",
+			cred:    config.Credential{},
+			wantErr: true,
+		},
+		{
+			name:         "env password",
+			cred:         config.Credential{Source: &config.CredentialSource{Type: "env", PasswordEnv: "TEST_ELOQUACTL_PW"}},
+			wantPassword: "from-env",
+		},
+		{
+			name:    "env password unset",
+			cred:    config.Credential{Source: &config.CredentialSource{Type: "env", PasswordEnv: "TEST_ELOQUACTL_UNSET"}},
+			wantErr: true,
+		},
+		{
+			name: "exec helper",
+			cred: config.Credential{
+				AuthProvider: config.AuthProviderOAuth2,
+				OAuth2:       &config.OAuth2{ClientID: "id"},
+				Source: &config.CredentialSource{
+					Type:    "exec",
+					Command: "sh",
+					Args:    []string{"-c", `echo "{\"clientSecret\": \"$PREFIX-$ELOQUACTL_CREDENTIALS\"}"`},
+					Env:     map[string]string{"PREFIX": "secret"},
+				},
+			},
+			wantSecret: "secret-acme",
+		},
+		{
+			name: "oauth2 without client secret",
+			cred: config.Credential{
+				AuthProvider: config.AuthProviderOAuth2,
+				OAuth2:       &config.OAuth2{ClientID: "id"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "oauth2 password grant without password",
+			cred: config.Credential{
+				AuthProvider: config.AuthProviderOAuth2,
+				OAuth2:       &config.OAuth2{ClientID: "id", ClientSecret: "s", Grant: config.GrantPassword},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cred := tt.cred
+			err := Fill("acme", &cred)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Fill() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cred.Password != tt.wantPassword {
+				t.Errorf("password = %q, want %q", cred.Password, tt.wantPassword)
+			}
+			if cred.OAuth2 != nil && cred.OAuth2.ClientSecret != tt.wantSecret {
+				t.Errorf("client secret = %q, want %q", cred.OAuth2.ClientSecret, tt.wantSecret)
+			}
+		})
+	}
+}
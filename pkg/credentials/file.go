@@ -0,0 +1,146 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// PassphraseEnv is the environment variable holding the passphrase of encrypted files.
+	PassphraseEnv = "ELOQUACTL_PASSPHRASE"
+
+	pbkdf2Iterations = 200000
+	// maxPbkdf2Iterations bounds the iterations read from a file, deriving
+	// the key from a crafted file would hang the command otherwise.
+	maxPbkdf2Iterations = 10 * pbkdf2Iterations
+	keyLen              = 32
+	saltLen             = 16
+)
+
+// PromptPassphrase asks the user for the passphrase when PassphraseEnv is not set.
+// Commands replace it with an interactive prompt.
+var PromptPassphrase = func() (string, error) {
+	return "", errors.New("the passphrase is required, set " + PassphraseEnv)
+}
+
+// File keeps the secrets in a file encrypted with AES-256-GCM. The key is
+// derived from a passphrase with PBKDF2-HMAC-SHA256.
+type File struct {
+	Path string
+}
+
+// encryptedFile is the on-disk format of the file.
+type encryptedFile struct {
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+func (s *File) Get(name string) (*Secrets, error) {
+	data, err := ioutil.ReadFile(s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	f := &encryptedFile{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, err
+	}
+
+	passphrase, err := passphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(passphrase, f.Salt, f.Iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := gcm.Open(nil, f.Nonce, f.Data, []byte(name))
+	if err != nil {
+		return nil, errors.New("failed to decrypt the credentials file, wrong passphrase?")
+	}
+
+	r := &Secrets{}
+	if err := json.Unmarshal(plain, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *File) Store(name string, secrets *Secrets) error {
+	passphrase, err := passphrase()
+	if err != nil {
+		return err
+	}
+
+	f := &encryptedFile{Iterations: pbkdf2Iterations, Salt: make([]byte, saltLen)}
+	if _, err := rand.Read(f.Salt); err != nil {
+		return err
+	}
+
+	gcm, err := newGCM(passphrase, f.Salt, f.Iterations)
+	if err != nil {
+		return err
+	}
+
+	f.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(f.Nonce); err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	// the credentials name is authenticated, so files can not be swapped
+	f.Data = gcm.Seal(nil, f.Nonce, plain, []byte(name))
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.Path, data, 0600)
+}
+
+func passphrase() (string, error) {
+	if p := os.Getenv(PassphraseEnv); p != "" {
+		return p, nil
+	}
+	return PromptPassphrase()
+}
+
+func newGCM(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	if iterations < pbkdf2Iterations || iterations > maxPbkdf2Iterations {
+		return nil, fmt.Errorf("the credentials file has %d PBKDF2 iterations, expected %d to %d", iterations, pbkdf2Iterations, maxPbkdf2Iterations)
+	}
+	if len(salt) < saltLen {
+		return nil, fmt.Errorf("the credentials file has a %d bytes salt, expected at least %d", len(salt), saltLen)
+	}
+
+	key := pbkdf2.Key([]byte(passphrase), salt, iterations, keyLen, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
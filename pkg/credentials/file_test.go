@@ -0,0 +1,127 @@
+package credentials
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tempFile returns a File in a new directory and a func removing it.
+func tempFile(t *testing.T) (*File, func()) {
+	dir, err := ioutil.TempDir("", "eloquactl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &File{Path: filepath.Join(dir, "secrets", "credentials.json")}, func() { os.RemoveAll(dir) }
+}
+
+func TestFileRoundTrip(t *testing.T) {
+	f, remove := tempFile(t)
+	defer remove()
+	defer setEnv(PassphraseEnv, "correct horse")()
+
+	want := &Secrets{Password: "pw", ClientSecret: "s3cret"}
+	if err := f.Store("acme", want); err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err := os.Stat(f.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0600 {
+		t.Errorf("file mode = %v, want 0600", perm)
+	}
+
+	data, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Errorf("the file holds the secret in clear: %s", data)
+	}
+
+	got, err := f.Get("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != *want {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFileGetFails(t *testing.T) {
+	f, remove := tempFile(t)
+	defer remove()
+	defer setEnv(PassphraseEnv, "correct horse")()
+	if err := f.Store("acme", &Secrets{Password: "pw"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		passphrase string
+		entry      string
+	}{
+		{name: "wrong passphrase", passphrase: "battery staple", entry: "acme"},
+		{name: "other credentials name", passphrase: "correct horse", entry: "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer setEnv(PassphraseEnv, tt.passphrase)()
+			if s, err := f.Get(tt.entry); err == nil {
+				t.Errorf("Get() = %+v, want an error", s)
+			}
+		})
+	}
+}
+
+func TestFileGetMissing(t *testing.T) {
+	f, remove := tempFile(t)
+	defer remove()
+
+	if _, err := f.Get("acme"); err != ErrNotFound {
+		t.Errorf("Get() = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestFileRejectsBadParameters(t *testing.T) {
+	defer setEnv(PassphraseEnv, "correct horse")()
+
+	tests := []struct {
+		name       string
+		iterations int
+		salt       []byte
+	}{
+		{name: "no iterations", iterations: 0, salt: make([]byte, saltLen)},
+		{name: "one iteration", iterations: 1, salt: make([]byte, saltLen)},
+		{name: "too many iterations", iterations: 1 << 40, salt: make([]byte, saltLen)},
+		{name: "no salt", iterations: pbkdf2Iterations},
+		{name: "short salt", iterations: pbkdf2Iterations, salt: make([]byte, saltLen-1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, remove := tempFile(t)
+			defer remove()
+			data, err := json.Marshal(&encryptedFile{Iterations: tt.iterations, Salt: tt.salt, Nonce: make([]byte, 12)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.MkdirAll(filepath.Dir(f.Path), 0700); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(f.Path, data, 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			if s, err := f.Get("acme"); err == nil {
+				t.Errorf("Get() = %+v, want an error", s)
+			}
+		})
+	}
+}
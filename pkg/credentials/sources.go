@@ -0,0 +1,136 @@
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const keyringService = "eloquactl"
+
+// Env reads the secrets from environment variables.
+type Env struct {
+	PasswordEnv     string
+	ClientSecretEnv string
+}
+
+func (s *Env) Get(name string) (*Secrets, error) {
+	r := &Secrets{
+		Password:     os.Getenv(orDefault(s.PasswordEnv, DefaultPasswordEnv)),
+		ClientSecret: os.Getenv(orDefault(s.ClientSecretEnv, DefaultClientSecretEnv)),
+	}
+
+	if r.Password == "" && r.ClientSecret == "" {
+		return nil, ErrNotFound
+	}
+	return r, nil
+}
+
+// Keyring keeps the secrets in the OS keyring through the Secret Service API.
+// It relies on the secret-tool utility shipped with libsecret.
+type Keyring struct{}
+
+// KeyringAvailable tells whether secret-tool is installed, i.e. whether the
+// secrets can be kept in the keyring.
+func KeyringAvailable() bool {
+	_, err := exec.LookPath("secret-tool")
+	return err == nil
+}
+
+func (s *Keyring) Get(name string) (*Secrets, error) {
+	password, err := keyringLookup(name, "password")
+	if err != nil {
+		return nil, err
+	}
+
+	clientSecret, err := keyringLookup(name, "client-secret")
+	if err != nil {
+		return nil, err
+	}
+
+	if password == "" && clientSecret == "" {
+		return nil, ErrNotFound
+	}
+	return &Secrets{Password: password, ClientSecret: clientSecret}, nil
+}
+
+func (s *Keyring) Store(name string, secrets *Secrets) error {
+	if secrets.Password != "" {
+		if err := keyringStore(name, "password", secrets.Password); err != nil {
+			return err
+		}
+	}
+
+	if secrets.ClientSecret != "" {
+		if err := keyringStore(name, "client-secret", secrets.ClientSecret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func keyringLookup(name, kind string) (string, error) {
+	cmd := exec.Command("secret-tool", "lookup", "service", keyringService, "credentials", name, "kind", kind)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok && stderr.Len() == 0 {
+			// secret-tool exits with 1 and no message when nothing matches
+			return "", nil
+		}
+		return "", fmt.Errorf("keyring lookup failed: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+func keyringStore(name, kind, secret string) error {
+	label := fmt.Sprintf("eloquactl %s %s", name, kind)
+	cmd := exec.Command("secret-tool", "store", "--label", label, "service", keyringService, "credentials", name, "kind", kind)
+	cmd.Stdin = strings.NewReader(secret)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("keyring store failed: %v %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Exec runs a credential helper command which prints the secrets as JSON
+// to stdout, e.g. {"password": "...", "clientSecret": "..."}.
+type Exec struct {
+	Command string
+	Args    []string
+	Env     map[string]string
+}
+
+func (s *Exec) Get(name string) (*Secrets, error) {
+	cmd := exec.Command(s.Command, s.Args...)
+	cmd.Env = append(os.Environ(), "ELOQUACTL_CREDENTIALS="+name)
+	for k, v := range s.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Stderr = os.Stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("credential helper %s failed: %v", s.Command, err)
+	}
+
+	r := &Secrets{}
+	if err := json.Unmarshal(out, r); err != nil {
+		return nil, fmt.Errorf("credential helper %s returned invalid JSON: %v", s.Command, err)
+	}
+	return r, nil
+}
+
+func orDefault(s, def string) string {
+	if s != "" {
+		return s
+	}
+	return def
+}
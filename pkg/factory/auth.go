@@ -0,0 +1,137 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/elqx/eloqua-go/eloqua/pkg/auth"
+	"github.com/elqx/eloquactl/pkg/config"
+	"github.com/elqx/eloquactl/pkg/credentials"
+	"github.com/elqx/eloquactl/pkg/oauth"
+	cmdutil "github.com/elqx/eloquactl/pkg/util"
+)
+
+func init() {
+	credentials.PromptPassphrase = func() (string, error) {
+		return cmdutil.PromptSecret("Passphrase")
+	}
+}
+
+// NewAuthClient returns an *http.Client that authenticates requests as the
+// user of the given context, using either basic auth or OAuth2 tokens.
+// Requests are sent through the base client's transport.
+func NewAuthClient(c *config.Context, cred *config.Credential, base *http.Client) (*http.Client, error) {
+	name := c.Credentials
+	if name == "" {
+		name = c.Name
+	}
+
+	if err := credentials.Fill(name, cred); err != nil {
+		return nil, &cmdutil.AuthError{Err: err}
+	}
+
+	switch cred.AuthProvider {
+	case "", config.AuthProviderBasic:
+		tr := &auth.BasicAuthTransport{Username: c.Username(), Password: cred.Password, Transport: base.Transport}
+		return &http.Client{Transport: tr, Timeout: base.Timeout}, nil
+	case config.AuthProviderOAuth2:
+		return newOAuth2Client(c, cred, base)
+	default:
+		return nil, fmt.Errorf("unknown auth provider %q in credentials %q", cred.AuthProvider, c.Credentials)
+	}
+}
+
+// NewOAuth2Config returns the OAuth2 client configuration of the credentials.
+// Token requests are sent with the base client.
+func NewOAuth2Config(cred *config.Credential, base *http.Client) (*oauth.Config, error) {
+	if cred.OAuth2 == nil || cred.OAuth2.ClientID == "" {
+		return nil, fmt.Errorf("oauth2 auth provider requires a client id")
+	}
+
+	return &oauth.Config{
+		ClientID:     cred.OAuth2.ClientID,
+		ClientSecret: cred.OAuth2.ClientSecret,
+		RedirectURL:  cred.OAuth2.RedirectURL,
+		AuthURL:      cred.OAuth2.AuthURL,
+		TokenURL:     cred.OAuth2.TokenURL,
+		HTTPClient:   base,
+	}, nil
+}
+
+// OAuth2Login obtains a new token for the context using the configured grant.
+func OAuth2Login(ctx context.Context, oc *oauth.Config, c *config.Context, cred *config.Credential) (*oauth.Token, error) {
+	var tok *oauth.Token
+	var err error
+
+	if cred.OAuth2.Grant == config.GrantPassword {
+		tok, err = oc.PasswordToken(ctx, c.Username(), cred.Password)
+	} else {
+		tok, err = oc.AuthorizeLocal(ctx, func(u string) {
+			fmt.Fprintf(os.Stderr, "Open the following URL in your browser to authorize eloquactl:\n\n    %s\n\n", u)
+		})
+	}
+
+	if err != nil {
+		return nil, &cmdutil.AuthError{Err: err}
+	}
+	return tok, nil
+}
+
+func newOAuth2Client(c *config.Context, cred *config.Credential, base *http.Client) (*http.Client, error) {
+	oc, err := NewOAuth2Config(cred, base)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := config.TokenCachePath(c.Name)
+	if err != nil {
+		return nil, err
+	}
+	store := &oauth.FileStore{Path: p}
+
+	tok, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	// a token that can not be refreshed requires a new login
+	if !tok.Valid() && (tok == nil || tok.RefreshToken == "") {
+		tok, err = OAuth2Login(context.Background(), oc, c, cred)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := store.Save(tok); err != nil {
+			return nil, err
+		}
+	}
+
+	tr := oauth.NewTransport(oc, store, tok)
+	tr.Transport = base.Transport
+	return &http.Client{Transport: tr, Timeout: base.Timeout}, nil
+}
+
+// AppCredentials returns the client id and secret of the App Cloud app of
+// the active context, the OAuth2 client of its credentials.
+func (f *Factory) AppCredentials() (string, string, error) {
+	c, cred, err := f.Context()
+	if err != nil {
+		return "", "", err
+	}
+
+	name := c.Credentials
+	if name == "" {
+		name = c.Name
+	}
+
+	if err := credentials.Fill(name, cred); err != nil {
+		return "", "", &cmdutil.AuthError{Err: err}
+	}
+
+	if cred.OAuth2 == nil || cred.OAuth2.ClientID == "" || cred.OAuth2.ClientSecret == "" {
+		return "", "", fmt.Errorf("context %q has no OAuth2 client, set --client-id and --client-secret", c.Name)
+	}
+	return cred.OAuth2.ClientID, cred.OAuth2.ClientSecret, nil
+}
@@ -0,0 +1,255 @@
+// Package factory builds the Bulk and REST API clients used by commands
+// from the active context and the global connection flags.
+package factory
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloqua-go/eloqua/rest"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/config"
+	"github.com/elqx/eloquactl/pkg/transport"
+	"github.com/spf13/pflag"
+)
+
+const (
+	DefaultAPIVersion = "2.0"
+)
+
+// Factory provides API clients to commands.
+type Factory struct {
+	// ContextName selects the config context, the current context if empty.
+	ContextName string
+
+	APIVersion     string
+	RequestTimeout time.Duration
+	Proxy          string
+	CABundle       string
+	Insecure       bool
+
+	// Retry and rate limit settings, applied to every API call.
+	MaxRetries    int
+	MaxConcurrent int
+	RateLimit     float64
+
+	// Metadata cache settings. A zero TTL disables the cache.
+	CacheTTL     time.Duration
+	RefreshCache bool
+
+	// DryRun makes export and import commands print the definition they
+	// would create instead of creating it.
+	DryRun bool
+
+	// Transport, if set, is used instead of a transport built from the
+	// connection flags, e.g. to run commands against a fake server.
+	Transport http.RoundTripper
+
+	// limiter is shared by all clients so that the limits hold for the
+	// whole process, not per client.
+	limiterOnce sync.Once
+	limiter     *transport.Limiter
+
+	// cache is shared by all commands, e.g. export jobs running in parallel.
+	cacheOnce sync.Once
+	cache     *cache.Cache
+	cacheErr  error
+}
+
+func New() *Factory {
+	return &Factory{
+		APIVersion:    DefaultAPIVersion,
+		MaxRetries:    transport.DefaultMaxRetries,
+		MaxConcurrent: transport.DefaultMaxConcurrent,
+		CacheTTL:      cache.DefaultTTL,
+	}
+}
+
+// AddFlags adds the global connection flags.
+func (f *Factory) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.ContextName, "context", f.ContextName, "The name of the config context to use")
+	flags.StringVar(&f.APIVersion, "api-version", f.APIVersion, "Version of the Bulk and REST APIs")
+	flags.DurationVar(&f.RequestTimeout, "request-timeout", f.RequestTimeout, "The time to wait for a single API request, e.g. 30s. Zero means no timeout")
+	flags.StringVar(&f.Proxy, "proxy", f.Proxy, "URL of the proxy to use, HTTPS_PROXY and HTTP_PROXY are used if not set")
+	flags.StringVar(&f.CABundle, "certificate-authority", f.CABundle, "Path to a PEM bundle of additional certificate authorities to trust")
+	flags.BoolVar(&f.Insecure, "insecure-skip-tls-verify", f.Insecure, "If true, the server's certificate will not be checked for validity")
+	flags.IntVar(&f.MaxRetries, "max-retries", f.MaxRetries, "How many times a failed idempotent API request is retried. Zero disables retries")
+	flags.IntVar(&f.MaxConcurrent, "max-concurrent-requests", f.MaxConcurrent, "Maximum number of API requests in flight at once. Zero means no limit")
+	flags.Float64Var(&f.RateLimit, "rate-limit", f.RateLimit, "Maximum number of API requests per second. Zero means no limit")
+	flags.DurationVar(&f.CacheTTL, "cache-ttl", f.CacheTTL, "How long fields, custom objects and lead scoring models are cached, e.g. 1h. Zero disables the cache")
+	flags.BoolVar(&f.RefreshCache, "refresh-cache", f.RefreshCache, "If true, cached metadata is fetched again from the API")
+	flags.BoolVar(&f.DryRun, "dry-run", f.DryRun, "If true, export and import commands print the definition they would create and exit without creating anything")
+}
+
+// Context returns the active context and its credentials.
+func (f *Factory) Context() (*config.Context, *config.Credential, error) {
+	return config.Resolve(f.ContextName)
+}
+
+// Cache returns the metadata cache of the active context, nil if the cache
+// is disabled.
+func (f *Factory) Cache() (*cache.Cache, error) {
+	f.cacheOnce.Do(func() {
+		if f.CacheTTL <= 0 {
+			return
+		}
+
+		c, _, err := f.Context()
+		if err != nil {
+			f.cacheErr = err
+			return
+		}
+
+		path, err := config.MetadataCachePath(c.Name)
+		if err != nil {
+			f.cacheErr = err
+			return
+		}
+
+		f.cache = cache.New(path, f.CacheTTL, f.RefreshCache)
+	})
+
+	return f.cache, f.cacheErr
+}
+
+// BaseClient returns an *http.Client without authentication, configured
+// with the connection flags.
+func (f *Factory) BaseClient() (*http.Client, error) {
+	tr, err := f.transport()
+	if err != nil {
+		return nil, err
+	}
+
+	f.limiterOnce.Do(func() {
+		f.limiter = transport.NewLimiter(f.MaxConcurrent, f.RateLimit)
+	})
+
+	// every attempt of a retried request waits for its turn in the limiter
+	tr = &transport.LimitTransport{Limiter: f.limiter, Transport: tr}
+	rt := transport.NewRetryTransport(tr, f.MaxRetries)
+	// the timeout is per attempt, it does not count the waits between retries
+	rt.Timeout = f.RequestTimeout
+
+	return &http.Client{Transport: rt}, nil
+}
+
+// HTTPClient returns an *http.Client authenticated as the user of the active context.
+func (f *Factory) HTTPClient() (*http.Client, error) {
+	c, cred, err := f.Context()
+	if err != nil {
+		return nil, err
+	}
+
+	return f.authClient(c, cred)
+}
+
+func (f *Factory) authClient(c *config.Context, cred *config.Credential) (*http.Client, error) {
+	base, err := f.BaseClient()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := NewAuthClient(c, cred, base)
+	if err != nil {
+		return nil, err
+	}
+
+	// outermost, so that the OAuth2 transport still sees 401 responses
+	client.Transport = &transport.StatusTransport{Transport: client.Transport}
+	return client, nil
+}
+
+// BulkClient returns a Bulk API client for the active context.
+func (f *Factory) BulkClient() (*bulk.BulkClient, error) {
+	c, cred, err := f.Context()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.BulkURL == "" {
+		return nil, fmt.Errorf("context %q has no Bulk API URL, run 'eloquactl login' or 'eloquactl config set-context %s --bulk-url=URL'", c.Name, c.Name)
+	}
+
+	httpClient, err := f.authClient(c, cred)
+	if err != nil {
+		return nil, err
+	}
+
+	return bulk.NewClient(c.BulkEndpoint(f.APIVersion), httpClient), nil
+}
+
+// RestClient returns a REST API client for the active context.
+func (f *Factory) RestClient() (*rest.RestClient, error) {
+	c, cred, err := f.Context()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.RestURL == "" {
+		return nil, fmt.Errorf("context %q has no REST API URL, run 'eloquactl login' or 'eloquactl config set-context %s --rest-url=URL'", c.Name, c.Name)
+	}
+
+	httpClient, err := f.authClient(c, cred)
+	if err != nil {
+		return nil, err
+	}
+
+	return rest.NewClient(c.RestEndpoint(f.APIVersion), httpClient), nil
+}
+
+func (f *Factory) transport() (http.RoundTripper, error) {
+	if f.Transport != nil {
+		return f.Transport, nil
+	}
+
+	tr := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	if f.Proxy != "" {
+		u, err := url.Parse(f.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %v", err)
+		}
+		tr.Proxy = http.ProxyURL(u)
+	}
+
+	if f.CABundle != "" || f.Insecure {
+		tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: f.Insecure}
+	}
+
+	if f.CABundle != "" {
+		pem, err := ioutil.ReadFile(f.CABundle)
+		if err != nil {
+			return nil, err
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates found in " + f.CABundle)
+		}
+		tr.TLSClientConfig.RootCAs = pool
+	}
+
+	return tr, nil
+}
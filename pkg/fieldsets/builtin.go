@@ -0,0 +1,198 @@
+package fieldsets
+
+// builtin are the field sets of the activity types, named after the type.
+var builtin = []FieldSet{
+	{
+		Name:   "EmailSend",
+		Entity: "activities",
+		Fields: map[string]string{
+			"ActivityId":                   "{{Activity.Id}}",
+			"ActivityType":                 "{{Activity.Type}}",
+			"EmailAddress":                 "{{Activity.Field(EmailAddress)}}",
+			"ContactId":                    "{{Activity.Contact.Id}}",
+			"EmailRecipientId":             "{{Activity.Field(EmailRecipientId)}}",
+			"AssetType":                    "{{Activity.Asset.Type}}",
+			"AssetId":                      "{{Activity.Asset.Id}}",
+			"AssetName":                    "{{Activity.Asset.Name}}",
+			"SubjectLine":                  "{{Activity.Field(SubjectLine)}}",
+			"EmailWebLink":                 "{{Activity.Field(EmailWebLink)}}",
+			"CampaignId":                   "{{Activity.Campaign.Id}}",
+			"ExternalId":                   "{{Activity.ExternalId}}",
+			"DeploymentId":                 "{{Activity.Field(EmailDeploymentId)}}",
+			"EmailSendType":                "{{Activity.Field(EmailSendType)}}",
+			"CampaignResponseDate":         "{{Activity.CampaignResponse.CreatedAt}}",
+			"CampaignResponseMemberStatus": "{{Activity.CampaignResponse.Field(MemberStatus)}}",
+		},
+	},
+	{
+		Name:   "EmailOpen",
+		Entity: "activities",
+		Fields: map[string]string{
+			"ActivityId":                   "{{Activity.Id}}",
+			"ActivityType":                 "{{Activity.Type}}",
+			"ActivityDate":                 "{{Activity.CreatedAt}}",
+			"EmailAddress":                 "{{Activity.Field(EmailAddress)}}",
+			"ContactId":                    "{{Activity.Contact.Id}}",
+			"IpAddress":                    "{{Activity.Field(IpAddress)}}",
+			"VisitorId":                    "{{Activity.Visitor.Id}}",
+			"EmailRecipientId":             "{{Activity.Field(EmailRecipientId)}}",
+			"AssetType":                    "{{Activity.Asset.Type}}",
+			"AssetName":                    "{{Activity.Asset.Name}}",
+			"AssetId":                      "{{Activity.Asset.Id}}",
+			"SubjectLine":                  "{{Activity.Field(SubjectLine)}}",
+			"EmailWebLink":                 "{{Activity.Field(EmailWebLink)}}",
+			"VisitorExternalId":            "{{Activity.Visitor.ExternalId}}",
+			"CampaignId":                   "{{Activity.Campaign.Id}}",
+			"ExternalId":                   "{{Activity.ExternalId}}",
+			"DeploymentId":                 "{{Activity.Field(EmailDeploymentId)}}",
+			"EmailSendType":                "{{Activity.Field(EmailSendType)}}",
+			"CampaignResponseDate":         "{{Activity.CampaignResponse.CreatedAt}}",
+			"CampaignResponseMemberStatus": "{{Activity.CampaignResponse.Field(MemberStatus)}}",
+		},
+	},
+	{
+		Name:   "EmailClickthrough",
+		Entity: "activities",
+		Fields: map[string]string{
+			"ActivityId":                   "{{Activity.Id}}",
+			"ActivityType":                 "{{Activity.Type}}",
+			"ActivityDate":                 "{{Activity.CreatedAt}}",
+			"EmailAddress":                 "{{Activity.Field(EmailAddress)}}",
+			"ContactId":                    "{{Activity.Contact.Id}}",
+			"IpAddress":                    "{{Activity.Field(IpAddress)}}",
+			"VisitorId":                    "{{Activity.Visitor.Id}}",
+			"EmailRecipientId":             "{{Activity.Field(EmailRecipientId)}}",
+			"AssetType":                    "{{Activity.Asset.Type}}",
+			"AssetName":                    "{{Activity.Asset.Name}}",
+			"AssetId":                      "{{Activity.Asset.Id}}",
+			"SubjectLine":                  "{{Activity.Field(SubjectLine)}}",
+			"EmailWebLink":                 "{{Activity.Field(EmailWebLink)}}",
+			"EmailClickedThruLink":         "{{Activity.Field(EmailClickedThruLink)}}",
+			"VisitorExternalId":            "{{Activity.Visitor.ExternalId}}",
+			"CampaignId":                   "{{Activity.Campaign.Id}}",
+			"ExternalId":                   "{{Activity.ExternalId}}",
+			"DeploymentId":                 "{{Activity.Field(EmailDeploymentId)}}",
+			"EmailSendType":                "{{Activity.Field(EmailSendType)}}",
+			"CampaignResponseDate":         "{{Activity.CampaignResponse.CreatedAt}}",
+			"CampaignResponseMemberStatus": "{{Activity.CampaignResponse.Field(MemberStatus)}}",
+		},
+	},
+	{
+		Name:   "Bounceback",
+		Entity: "activities",
+		Fields: map[string]string{
+			"ActivityId":       "{{Activity.Id}}",
+			"ActivityType":     "{{Activity.Type}}",
+			"ActivityDate":     "{{Activity.CreatedAt}}",
+			"EmailAddress":     "{{Activity.Field(EmailAddress)}}",
+			"ContactId":        "{{Activity.Contact.Id}}",
+			"AssetType":        "{{Activity.Asset.Type}}",
+			"AssetName":        "{{Activity.Asset.Name}}",
+			"AssetId":          "{{Activity.Asset.Id}}",
+			"CampaignId":       "{{Activity.Campaign.Id}}",
+			"ExternalId":       "{{Activity.ExternalId}}",
+			"EmailRecipientId": "{{Activity.Field(EmailRecipientId)}}",
+			"DeploymentId":     "{{Activity.Field(EmailDeploymentId)}}",
+			"SmtpErrorCode":    "{{Activity.Field(SmtpErrorCode)}}",
+			"SmtpStatusCode":   "{{Activity.Field(SmtpStatusCode)}}",
+			"SmtpMessage":      "{{Activity.Field(SmtpMessage)}}",
+		},
+	},
+	{
+		Name:   "FormSubmit",
+		Entity: "activities",
+		Fields: map[string]string{
+			"ActivityId":                   "{{Activity.Id}}",
+			"ActivityType":                 "{{Activity.Type}}",
+			"ActivityDate":                 "{{Activity.CreatedAt}}",
+			"ContactId":                    "{{Activity.Contact.Id}}",
+			"VisitorId":                    "{{Activity.Visitor.Id}}",
+			"VisitorExternalId":            "{{Activity.Visitor.ExternalId}}",
+			"AssetType":                    "{{Activity.Asset.Type}}",
+			"AssetId":                      "{{Activity.Asset.Id}}",
+			"AssetName":                    "{{Activity.Asset.Name}}",
+			"RawData":                      "{{Activity.Field(RawData)}}",
+			"CampaignId":                   "{{Activity.Campaign.Id}}",
+			"ExternalId":                   "{{Activity.ExternalId}}",
+			"CampaignResponseDate":         "{{Activity.CampaignResponse.CreatedAt}}",
+			"CampaignResponseMemberStatus": "{{Activity.CampaignResponse.Field(MemberStatus)}}",
+		},
+	},
+	{
+		Name:   "Subscribe",
+		Entity: "activities",
+		Fields: map[string]string{
+			"ActivityId":       "{{Activity.Id}}",
+			"ActivityType":     "{{Activity.Type}}",
+			"AssetId":          "{{Activity.Asset.Id}}",
+			"ActivityDate":     "{{Activity.CreatedAt}}",
+			"EmailAddress":     "{{Activity.Field(EmailAddress)}}",
+			"ContactId":        "{{Activity.Contact.Id}}",
+			"EmailRecipientId": "{{Activity.Field(EmailRecipientId)}}",
+			"AssetType":        "{{Activity.Asset.Type}}",
+			"AssetName":        "{{Activity.Asset.Name}}",
+			"CampaignId":       "{{Activity.Campaign.Id}}",
+			"ExternalId":       "{{Activity.ExternalId}}",
+		},
+	},
+	{
+		Name:   "Unsubscribe",
+		Entity: "activities",
+		Fields: map[string]string{
+			"ActivityId":       "{{Activity.Id}}",
+			"ActivityType":     "{{Activity.Type}}",
+			"AssetId":          "{{Activity.Asset.Id}}",
+			"ActivityDate":     "{{Activity.CreatedAt}}",
+			"EmailAddress":     "{{Activity.Field(EmailAddress)}}",
+			"ContactId":        "{{Activity.Contact.Id}}",
+			"EmailRecipientId": "{{Activity.Field(EmailRecipientId)}}",
+			"AssetType":        "{{Activity.Asset.Type}}",
+			"AssetName":        "{{Activity.Asset.Name}}",
+			"CampaignId":       "{{Activity.Campaign.Id}}",
+			"ExternalId":       "{{Activity.ExternalId}}",
+		},
+	},
+	{
+		Name:   "WebVisit",
+		Entity: "activities",
+		Fields: map[string]string{
+			"ActivityId":          "{{Activity.Id}}",
+			"ActivityType":        "{{Activity.Type}}",
+			"ActivityDate":        "{{Activity.CreatedAt}}",
+			"ContactId":           "{{Activity.Contact.Id}}",
+			"VisitorId":           "{{Activity.Visitor.Id}}",
+			"VisitorExternalId":   "{{Activity.Visitor.ExternalId}}",
+			"ReferrerUrl":         "{{Activity.Field(ReferrerUrl)}}",
+			"IpAddress":           "{{Activity.Field(IpAddress)}}",
+			"NumberOfPages":       "{{Activity.Field(NumberOfPages)}}",
+			"FirstPageViewUrl":    "{{Activity.Field(FirstPageViewUrl)}}",
+			"Duration":            "{{Activity.Field(Duration)}}",
+			"ExternalId":          "{{Activity.ExternalId}}",
+			"LinkedToContactDate": "{{Activity.Field(LinkedToContactDate)}}",
+			"WebVisitSavedId":     "{{Activity.Field(WebVisitSavedId)}}",
+		},
+	},
+	{
+		Name:   "PageView",
+		Entity: "activities",
+		Fields: map[string]string{
+			"ActivityId":                   "{{Activity.Id}}",
+			"ActivityType":                 "{{Activity.Type}}",
+			"ActivityDate":                 "{{Activity.CreatedAt}}",
+			"ContactId":                    "{{Activity.Contact.Id}}",
+			"CampaignId":                   "{{Activity.Campaign.Id}}",
+			"VisitorId":                    "{{Activity.Visitor.Id}}",
+			"VisitorExternalId":            "{{Activity.Visitor.ExternalId}}",
+			"WebVisitId":                   "{{Activity.Field(WebVisitId)}}",
+			"Url":                          "{{Activity.Field(Url)}}",
+			"ReferrerUrl":                  "{{Activity.Field(ReferrerUrl)}}",
+			"IpAddress":                    "{{Activity.Field(IpAddress)}}",
+			"IsWebTrackingOptedIn":         "{{Activity.Field(IsWebTrackingOptedIn)}}",
+			"ExternalId":                   "{{Activity.ExternalId}}",
+			"LinkedToContactDate":          "{{Activity.Field(LinkedToContactDate)}}",
+			"PageViewSavedId":              "{{Activity.Field(PageViewSavedId)}}",
+			"CampaignResponseDate":         "{{Activity.CampaignResponse.CreatedAt}}",
+			"CampaignResponseMemberStatus": "{{Activity.CampaignResponse.Field(MemberStatus)}}",
+		},
+	},
+}
@@ -0,0 +1,72 @@
+// Package fieldsets provides named sets of export fields: the built-in ones
+// of the activity types and the ones defined in the config file.
+package fieldsets
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/elqx/eloquactl/pkg/config"
+)
+
+const (
+	SourceBuiltin = "builtin"
+	SourceConfig  = "config"
+)
+
+// FieldSet is a named set of export fields of an entity type.
+type FieldSet struct {
+	Name   string            `json:"name"`
+	Entity string            `json:"entity"`
+	Source string            `json:"source"`
+	Fields map[string]string `json:"fields"`
+}
+
+// List returns the field sets of the entity type, all of them if entity is
+// empty, sorted by entity and name. A set in the config file replaces the
+// built-in set with the same name.
+func List(entity string) ([]FieldSet, error) {
+	c, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	sets := map[string]FieldSet{}
+	for _, s := range builtin {
+		s.Source = SourceBuiltin
+		sets[s.Entity+"/"+s.Name] = s
+	}
+	for _, s := range c.FieldSets {
+		sets[s.Entity+"/"+s.Name] = FieldSet{Name: s.Name, Entity: s.Entity, Source: SourceConfig, Fields: s.Fields}
+	}
+
+	var list []FieldSet
+	for _, s := range sets {
+		if entity == "" || s.Entity == entity {
+			list = append(list, s)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Entity != list[j].Entity {
+			return list[i].Entity < list[j].Entity
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// Get returns the field set of the entity type with the given name.
+func Get(entity, name string) (*FieldSet, error) {
+	sets, err := List(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sets {
+		if sets[i].Name == name {
+			return &sets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("field set %q of %s not found, see 'eloquactl get field-sets'", name, entity)
+}
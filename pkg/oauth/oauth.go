@@ -0,0 +1,288 @@
+// Package oauth implements the OAuth2 flows supported by Eloqua: the
+// authorization code grant, the resource owner password grant and
+// refresh token rotation.
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	DefaultAuthURL     = "https://login.eloqua.com/auth/oauth2/authorize"
+	DefaultTokenURL    = "https://login.eloqua.com/auth/oauth2/token"
+	DefaultRedirectURL = "http://localhost:8085/callback"
+	DefaultScope       = "full"
+
+	// expiryDelta is how much earlier a token is considered expired,
+	// so it does not expire in the middle of a request.
+	expiryDelta = 30 * time.Second
+)
+
+var ErrNoRefreshToken = errors.New("oauth2: the token has expired and there is no refresh token, log in again")
+
+// Config describes an OAuth2 client registered in Eloqua as an App Cloud app.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	TokenURL     string
+	RedirectURL  string
+	Scope        string
+
+	// HTTPClient is used for token requests. http.DefaultClient if nil.
+	HTTPClient *http.Client
+}
+
+// Token is an OAuth2 token issued by Eloqua.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+// tokenResponse is the body of a token endpoint response.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Valid reports whether the token has an access token that is not expired.
+func (t *Token) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(expiryDelta).Before(t.Expiry)
+}
+
+func (c *Config) authURL() string {
+	if c.AuthURL != "" {
+		return c.AuthURL
+	}
+	return DefaultAuthURL
+}
+
+func (c *Config) tokenURL() string {
+	if c.TokenURL != "" {
+		return c.TokenURL
+	}
+	return DefaultTokenURL
+}
+
+func (c *Config) redirectURL() string {
+	if c.RedirectURL != "" {
+		return c.RedirectURL
+	}
+	return DefaultRedirectURL
+}
+
+func (c *Config) scope() string {
+	if c.Scope != "" {
+		return c.Scope
+	}
+	return DefaultScope
+}
+
+func (c *Config) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// AuthCodeURL returns the URL of the Eloqua consent page.
+func (c *Config) AuthCodeURL(state string) string {
+	v := url.Values{
+		"response_type": {"code"},
+		"client_id":     {c.ClientID},
+		"redirect_uri":  {c.redirectURL()},
+		"scope":         {c.scope()},
+		"state":         {state},
+	}
+	return c.authURL() + "?" + v.Encode()
+}
+
+// Exchange converts an authorization code into a token.
+func (c *Config) Exchange(ctx context.Context, code string) (*Token, error) {
+	return c.retrieveToken(ctx, map[string]string{
+		"grant_type":   "authorization_code",
+		"code":         code,
+		"redirect_uri": c.redirectURL(),
+	})
+}
+
+// PasswordToken obtains a token using the resource owner password grant.
+// The username is expected in the 'company\user' form.
+func (c *Config) PasswordToken(ctx context.Context, username, password string) (*Token, error) {
+	return c.retrieveToken(ctx, map[string]string{
+		"grant_type": "password",
+		"scope":      c.scope(),
+		"username":   username,
+		"password":   password,
+	})
+}
+
+// Refresh obtains a new token using the refresh token. Eloqua rotates refresh
+// tokens, so the returned token must replace the old one.
+func (c *Config) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	t, err := c.retrieveToken(ctx, map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": refreshToken,
+		"scope":         c.scope(),
+		"redirect_uri":  c.redirectURL(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the refresh token may be left out of the response (RFC 6749 section 6),
+	// the one sent stays valid then
+	if t.RefreshToken == "" {
+		t.RefreshToken = refreshToken
+	}
+	return t, nil
+}
+
+func (c *Config) retrieveToken(ctx context.Context, params map[string]string) (*Token, error) {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", c.tokenURL(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.SetBasicAuth(c.ClientID, c.ClientSecret)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &tokenResponse{}
+	if err := json.Unmarshal(data, r); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("oauth2: cannot parse token response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || r.AccessToken == "" {
+		msg := r.ErrorDescription
+		if msg == "" {
+			msg = r.Error
+		}
+		if msg == "" {
+			msg = http.StatusText(resp.StatusCode)
+		}
+		return nil, fmt.Errorf("oauth2: %s grant failed: %s", params["grant_type"], msg)
+	}
+
+	t := &Token{
+		AccessToken:  r.AccessToken,
+		TokenType:    r.TokenType,
+		RefreshToken: r.RefreshToken,
+	}
+	if r.ExpiresIn > 0 {
+		t.Expiry = time.Now().Add(time.Duration(r.ExpiresIn) * time.Second)
+	}
+
+	return t, nil
+}
+
+// AuthorizeLocal runs the authorization code flow. It starts a listener on
+// the redirect URL, calls open with the consent page URL and waits for Eloqua
+// to redirect the browser back with the authorization code.
+func (c *Config) AuthorizeLocal(ctx context.Context, open func(authURL string)) (*Token, error) {
+	u, err := url.Parse(c.redirectURL())
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		code string
+		err  error
+	}
+	done := make(chan result, 1)
+	finish := func(r result) {
+		// only the first callback counts
+		select {
+		case done <- r:
+		default:
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(u.Path, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case q.Get("state") != state:
+			http.Error(w, "state mismatch", http.StatusBadRequest)
+			finish(result{err: errors.New("oauth2: state mismatch in authorization callback")})
+		case q.Get("error") != "":
+			http.Error(w, q.Get("error"), http.StatusBadRequest)
+			finish(result{err: fmt.Errorf("oauth2: authorization failed: %s", q.Get("error"))})
+		default:
+			fmt.Fprintln(w, "Authorization completed, you can close this window.")
+			finish(result{code: q.Get("code")})
+		}
+	})
+
+	srv := &http.Server{Addr: u.Host, Handler: mux}
+	errc := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			errc <- err
+		}
+	}()
+	defer srv.Close()
+
+	open(c.AuthCodeURL(state))
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case err := <-errc:
+		return nil, err
+	case r := <-done:
+		if r.err != nil {
+			return nil, r.err
+		}
+		return c.Exchange(ctx, r.code)
+	}
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
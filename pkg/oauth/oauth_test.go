@@ -0,0 +1,108 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// tokenServer is a token endpoint rotating refresh tokens: a refresh token
+// can be used once.
+type tokenServer struct {
+	*httptest.Server
+
+	// keepRefreshToken leaves refresh_token out of the responses.
+	keepRefreshToken bool
+
+	mu        sync.Mutex
+	refreshes int
+	valid     map[string]bool
+}
+
+func newTokenServer(refreshToken string) *tokenServer {
+	s := &tokenServer{valid: map[string]bool{refreshToken: true}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveToken))
+	return s
+}
+
+func (s *tokenServer) serveToken(w http.ResponseWriter, r *http.Request) {
+	params := map[string]string{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil || params["grant_type"] != "refresh_token" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.valid[params["refresh_token"]] {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	s.refreshes++
+	resp := map[string]interface{}{
+		"access_token": fmt.Sprintf("access%d", s.refreshes),
+		"token_type":   "bearer",
+		"expires_in":   28800,
+	}
+	if !s.keepRefreshToken {
+		delete(s.valid, params["refresh_token"])
+		next := fmt.Sprintf("refresh%d", s.refreshes)
+		s.valid[next] = true
+		resp["refresh_token"] = next
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *tokenServer) config() *Config {
+	return &Config{ClientID: "id", ClientSecret: "secret", TokenURL: s.URL}
+}
+
+func TestRefresh(t *testing.T) {
+	tests := []struct {
+		name             string
+		keepRefreshToken bool
+		want             []string
+	}{
+		{name: "rotated", want: []string{"refresh1", "refresh2", "refresh3"}},
+		{name: "left out of the response", keepRefreshToken: true, want: []string{"refresh0", "refresh0", "refresh0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTokenServer("refresh0")
+			srv.keepRefreshToken = tt.keepRefreshToken
+			defer srv.Close()
+
+			refreshToken := "refresh0"
+			for i, want := range tt.want {
+				tok, err := srv.config().Refresh(context.Background(), refreshToken)
+				if err != nil {
+					t.Fatalf("refresh %d: %v", i, err)
+				}
+				if tok.RefreshToken != want {
+					t.Errorf("refresh %d: refresh token = %q, want %q", i, tok.RefreshToken, want)
+				}
+				if !tok.Valid() {
+					t.Errorf("refresh %d: token is not valid", i)
+				}
+				refreshToken = tok.RefreshToken
+			}
+		})
+	}
+}
+
+func TestRefreshRejected(t *testing.T) {
+	srv := newTokenServer("refresh0")
+	defer srv.Close()
+
+	if tok, err := srv.config().Refresh(context.Background(), "revoked"); err == nil {
+		t.Errorf("Refresh() = %+v, want an error", tok)
+	}
+}
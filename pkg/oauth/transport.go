@@ -0,0 +1,166 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// TokenStore persists tokens between runs.
+type TokenStore interface {
+	Load() (*Token, error)
+	Save(*Token) error
+}
+
+// FileStore keeps a token in a JSON file readable only by the owner.
+type FileStore struct {
+	Path string
+}
+
+// Load returns the cached token, or nil if there is none.
+func (s *FileStore) Load() (*Token, error) {
+	data, err := ioutil.ReadFile(s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	t := &Token{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *FileStore) Save(t *Token) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.Path, data, 0600)
+}
+
+// TokenError is returned by Transport when no valid token can be obtained.
+type TokenError struct {
+	Err error
+}
+
+func (e *TokenError) Error() string { return e.Err.Error() }
+
+// Transport is an http.RoundTripper that authenticates requests with a bearer
+// token, refreshing it when it expires and saving the rotated token to Store.
+type Transport struct {
+	Config *Config
+	Store  TokenStore
+
+	// Transport is the underlying HTTP transport to use when making requests
+	Transport http.RoundTripper
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewTransport returns a Transport starting with the given token.
+func NewTransport(c *Config, store TokenStore, t *Token) *Transport {
+	return &Transport{Config: c, Store: store, token: t}
+}
+
+// RoundTrip implements the RoundTripper interface
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tok, err := t.Token(req.Context(), nil)
+	if err != nil {
+		return nil, &TokenError{Err: err}
+	}
+
+	resp, err := t.transport().RoundTrip(authorize(req, tok))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || tok.RefreshToken == "" {
+		return resp, err
+	}
+
+	// The token was revoked or expired early. Refresh and retry once
+	// if the request body can be replayed.
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	tok, err = t.Token(req.Context(), tok)
+	if err != nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	retry := authorize(req, tok)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return t.transport().RoundTrip(retry)
+}
+
+// Token returns a valid token, refreshing it if it is expired or if it is
+// still the rejected token, one the server answered 401 to. Requests rejected
+// at the same time thus share one refresh: refreshing again would rotate the
+// refresh token the first refresh got.
+func (t *Transport) Token(ctx context.Context, rejected *Token) (*Token, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token.Valid() && (rejected == nil || t.token.AccessToken != rejected.AccessToken) {
+		return t.token, nil
+	}
+
+	if t.token == nil || t.token.RefreshToken == "" {
+		return nil, ErrNoRefreshToken
+	}
+
+	tok, err := t.Config.Refresh(ctx, t.token.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Store != nil {
+		if err := t.Store.Save(tok); err != nil {
+			return nil, err
+		}
+	}
+	t.token = tok
+	return tok, nil
+}
+
+// Client returns an *http.Client that makes requests authenticated with OAuth2 tokens.
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+func (t *Transport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return http.DefaultTransport
+}
+
+func authorize(req *http.Request, tok *Token) *http.Request {
+	// clone the request
+	reqClone := new(http.Request)
+	*reqClone = *req
+
+	// deep copy req.Header
+	reqClone.Header = make(http.Header, len(req.Header))
+	for id, header := range req.Header {
+		reqClone.Header[id] = append([]string(nil), header...)
+	}
+
+	reqClone.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	return reqClone
+}
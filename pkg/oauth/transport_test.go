@@ -0,0 +1,141 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryStore is a TokenStore keeping the last saved token.
+type memoryStore struct {
+	mu    sync.Mutex
+	token *Token
+}
+
+func (s *memoryStore) Load() (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token, nil
+}
+
+func (s *memoryStore) Save(t *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = t
+	return nil
+}
+
+// apiServer accepts requests with the given access token only.
+func apiServer(accessToken string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+accessToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+}
+
+func TestTransport(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         *Token
+		wantStatus    int
+		wantRefreshes int
+		wantSaved     string
+	}{
+		{
+			name:       "valid token",
+			token:      &Token{AccessToken: "access1", RefreshToken: "refresh0"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:          "expired token",
+			token:         &Token{AccessToken: "access0", RefreshToken: "refresh0", Expiry: time.Now().Add(-time.Minute)},
+			wantStatus:    http.StatusOK,
+			wantRefreshes: 1,
+			wantSaved:     "refresh1",
+		},
+		{
+			name:          "revoked token",
+			token:         &Token{AccessToken: "access0", RefreshToken: "refresh0"},
+			wantStatus:    http.StatusOK,
+			wantRefreshes: 1,
+			wantSaved:     "refresh1",
+		},
+		{
+			name:       "revoked token without refresh token",
+			token:      &Token{AccessToken: "access0"},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := newTokenServer("refresh0")
+			defer tokens.Close()
+			api := apiServer("access1")
+			defer api.Close()
+
+			store := &memoryStore{}
+			client := NewTransport(tokens.config(), store, tt.token).Client()
+
+			resp, err := client.Post(api.URL, "application/json", strings.NewReader("{}"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tokens.refreshes != tt.wantRefreshes {
+				t.Errorf("%d refreshes, want %d", tokens.refreshes, tt.wantRefreshes)
+			}
+			if saved, _ := store.Load(); tt.wantSaved != "" && (saved == nil || saved.RefreshToken != tt.wantSaved) {
+				t.Errorf("saved token = %+v, want refresh token %q", saved, tt.wantSaved)
+			}
+		})
+	}
+}
+
+func TestTransportConcurrentRefresh(t *testing.T) {
+	tokens := newTokenServer("refresh0")
+	defer tokens.Close()
+	api := apiServer("access1")
+	defer api.Close()
+
+	client := NewTransport(tokens.config(), &memoryStore{}, &Token{AccessToken: "access0", RefreshToken: "refresh0"}).Client()
+
+	const requests = 10
+	statuses := make(chan int, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(api.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	for status := range statuses {
+		if status != http.StatusOK {
+			t.Errorf("status = %d, want %d", status, http.StatusOK)
+		}
+	}
+	// a second refresh would have used the rotated refresh0
+	if tokens.refreshes != 1 {
+		t.Errorf("%d refreshes, want 1", tokens.refreshes)
+	}
+}
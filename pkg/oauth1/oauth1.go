@@ -0,0 +1,297 @@
+// Package oauth1 implements OAuth 1.0a HMAC-SHA1 signatures, which Eloqua
+// uses to sign its calls to App Cloud apps and services with the client id
+// and secret of the app.
+package oauth1
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	SignatureMethod = "HMAC-SHA1"
+	Version         = "1.0"
+
+	// DefaultMaxSkew is how far the timestamp of a request may be from the
+	// current time. Zero skips the timestamp and nonce checks.
+	DefaultMaxSkew = 5 * time.Minute
+)
+
+var (
+	ErrMissingSignature = errors.New("oauth1: the request is not signed")
+	ErrInvalidSignature = errors.New("oauth1: invalid signature")
+	ErrReplayedNonce    = errors.New("oauth1: the nonce was already used")
+)
+
+// Verifier checks the signatures of requests signed by a consumer.
+type Verifier struct {
+	ConsumerKey    string
+	ConsumerSecret string
+	MaxSkew        time.Duration
+
+	mu     sync.Mutex
+	nonces map[string]time.Time
+}
+
+func NewVerifier(consumerKey, consumerSecret string) *Verifier {
+	return &Verifier{
+		ConsumerKey:    consumerKey,
+		ConsumerSecret: consumerSecret,
+		MaxSkew:        DefaultMaxSkew,
+		nonces:         map[string]time.Time{},
+	}
+}
+
+// Verify checks the signature of a request sent to rawURL with the given
+// parameters, those of the query, form body and Authorization header.
+// Nonces are remembered for MaxSkew, so a request cannot be replayed.
+// The timestamp and nonce are not checked when MaxSkew is zero, e.g. for
+// captured requests.
+func (v *Verifier) Verify(method, rawURL string, params url.Values) error {
+	signature := params.Get("oauth_signature")
+	if signature == "" {
+		return ErrMissingSignature
+	}
+
+	if key := params.Get("oauth_consumer_key"); key != v.ConsumerKey {
+		return fmt.Errorf("oauth1: unknown consumer key %q", key)
+	}
+
+	if m := params.Get("oauth_signature_method"); m != SignatureMethod {
+		return fmt.Errorf("oauth1: unsupported signature method %q", m)
+	}
+
+	if version := params.Get("oauth_version"); version != "" && version != Version {
+		return fmt.Errorf("oauth1: unsupported version %q", version)
+	}
+
+	ts, err := strconv.ParseInt(params.Get("oauth_timestamp"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("oauth1: invalid timestamp %q", params.Get("oauth_timestamp"))
+	}
+	now := time.Now()
+	if skew := now.Sub(time.Unix(ts, 0)); v.MaxSkew > 0 && (skew > v.MaxSkew || skew < -v.MaxSkew) {
+		return fmt.Errorf("oauth1: the timestamp is %v off", skew.Round(time.Millisecond))
+	}
+
+	base, err := SignatureBase(method, rawURL, params)
+	if err != nil {
+		return err
+	}
+	expected := Signature(base, v.ConsumerSecret, "")
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ErrInvalidSignature
+	}
+
+	if v.MaxSkew <= 0 {
+		return nil
+	}
+	return v.useNonce(params.Get("oauth_nonce"), now)
+}
+
+func (v *Verifier) useNonce(nonce string, now time.Time) error {
+	if nonce == "" {
+		return errors.New("oauth1: missing nonce")
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for n, t := range v.nonces {
+		if now.Sub(t) > 2*v.MaxSkew {
+			delete(v.nonces, n)
+		}
+	}
+
+	if _, ok := v.nonces[nonce]; ok {
+		return ErrReplayedNonce
+	}
+	v.nonces[nonce] = now
+	return nil
+}
+
+// Signer signs requests as Eloqua signs its App Cloud calls, with the
+// oauth_ parameters in the query.
+type Signer struct {
+	ConsumerKey    string
+	ConsumerSecret string
+}
+
+func NewSigner(consumerKey, consumerSecret string) *Signer {
+	return &Signer{ConsumerKey: consumerKey, ConsumerSecret: consumerSecret}
+}
+
+// SignURL returns rawURL with the oauth_ parameters and the signature of a
+// request with the given method added to its query.
+func (s *Signer) SignURL(method, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	params := u.Query()
+	params.Set("oauth_consumer_key", s.ConsumerKey)
+	params.Set("oauth_nonce", hex.EncodeToString(nonce))
+	params.Set("oauth_signature_method", SignatureMethod)
+	params.Set("oauth_timestamp", strconv.FormatInt(time.Now().Unix(), 10))
+	params.Set("oauth_version", Version)
+
+	base, err := SignatureBase(method, rawURL, params)
+	if err != nil {
+		return "", err
+	}
+	params.Set("oauth_signature", Signature(base, s.ConsumerSecret, ""))
+
+	u.RawQuery = params.Encode()
+	return u.String(), nil
+}
+
+// SignRequest signs a request with a body that is not a form, e.g. JSON.
+func (s *Signer) SignRequest(r *http.Request) error {
+	signed, err := s.SignURL(r.Method, r.URL.String())
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(signed)
+	if err != nil {
+		return err
+	}
+	r.URL = u
+	return nil
+}
+
+// RequestParams returns the parameters of a request that are signed: the
+// query, the form body and the oauth_ parameters of the Authorization header.
+// The form body is read and replaced, so it can be read again.
+func RequestParams(r *http.Request) (url.Values, error) {
+	params := url.Values{}
+	for k, vs := range r.URL.Query() {
+		params[k] = append(params[k], vs...)
+	}
+
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if r.Body != nil && ct == "application/x-www-form-urlencoded" {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		r.Body = ioutil.NopCloser(strings.NewReader(string(body)))
+
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, fmt.Errorf("oauth1: invalid form body: %v", err)
+		}
+		for k, vs := range form {
+			params[k] = append(params[k], vs...)
+		}
+	}
+
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "OAuth ") {
+		for _, p := range strings.Split(strings.TrimPrefix(auth, "OAuth "), ",") {
+			kv := strings.SplitN(strings.TrimSpace(p), "=", 2)
+			if len(kv) != 2 || kv[0] == "realm" {
+				continue
+			}
+			k, err := url.QueryUnescape(kv[0])
+			if err != nil {
+				return nil, fmt.Errorf("oauth1: invalid Authorization header: %v", err)
+			}
+			v, err := url.QueryUnescape(strings.Trim(kv[1], `"`))
+			if err != nil {
+				return nil, fmt.Errorf("oauth1: invalid Authorization header: %v", err)
+			}
+			params.Add(k, v)
+		}
+	}
+
+	return params, nil
+}
+
+// SignatureBase returns the signature base string of a request, RFC 5849
+// section 3.4.1. The query of rawURL is ignored, its parameters must be in
+// params. oauth_signature is left out.
+func SignatureBase(method, rawURL string, params url.Values) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("oauth1: %q is not an absolute URL", rawURL)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+	if scheme == "http" && strings.HasSuffix(host, ":80") || scheme == "https" && strings.HasSuffix(host, ":443") {
+		host = host[:strings.LastIndex(host, ":")]
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	// pairs are sorted by encoded name, then value, not as "name=value"
+	// strings: "a=1" would sort after "a-b=2"
+	var pairs [][2]string
+	for k, vs := range params {
+		if k == "oauth_signature" {
+			continue
+		}
+		for _, v := range vs {
+			pairs = append(pairs, [2]string{escape(k), escape(v)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+
+	normalized := make([]string, len(pairs))
+	for n, p := range pairs {
+		normalized[n] = p[0] + "=" + p[1]
+	}
+
+	return strings.ToUpper(method) + "&" + escape(scheme+"://"+host+path) + "&" + escape(strings.Join(normalized, "&")), nil
+}
+
+// Signature returns the HMAC-SHA1 signature of the signature base string.
+func Signature(base, consumerSecret, tokenSecret string) string {
+	mac := hmac.New(sha1.New, []byte(escape(consumerSecret)+"&"+escape(tokenSecret)))
+	mac.Write([]byte(base))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// escape percent-encodes s as in RFC 3986, all but the unreserved characters.
+func escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,185 @@
+package oauth1
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// rfcRequest is the example request of RFC 5849 section 3.4.1.
+func rfcRequest(t *testing.T) *http.Request {
+	r, err := http.NewRequest("POST", "http://example.com/request?b5=%3D%253D&a3=a&c%40=&a2=r%20b", strings.NewReader("c2&a3=2+q"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Authorization", `OAuth realm="Example", oauth_consumer_key="9djdj82h48djs9d2", oauth_token="kkk9d7dh3k39sjv7", oauth_signature_method="HMAC-SHA1", oauth_timestamp="137131201", oauth_nonce="7d8f3e4a", oauth_signature="bYT5CMsGcbgUdFHObYMEfcx6bsw%3D"`)
+	return r
+}
+
+func TestSignatureBase(t *testing.T) {
+	rfcParams, err := RequestParams(rfcRequest(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		params url.Values
+		want   string
+	}{
+		{
+			name:   "RFC 5849 section 3.4.1.1",
+			method: "POST",
+			url:    "http://example.com/request?b5=%3D%253D&a3=a&c%40=&a2=r%20b",
+			params: rfcParams,
+			want: "POST&http%3A%2F%2Fexample.com%2Frequest&a2%3Dr%2520b%26a3%3D2%2520q" +
+				"%26a3%3Da%26b5%3D%253D%25253D%26c%2540%3D%26c2%3D%26oauth_consumer_key%3D9dj" +
+				"dj82h48djs9d2%26oauth_nonce%3D7d8f3e4a%26oauth_signature_method%3DHMAC-SHA1" +
+				"%26oauth_timestamp%3D137131201%26oauth_token%3Dkkk9d7dh3k39sjv7",
+		},
+		{
+			name:   "a name sorts before the longer names it prefixes",
+			method: "GET",
+			url:    "https://example.com/notify",
+			params: url.Values{"a-b": {"2"}, "a": {"1"}, "a.b": {"3"}, "a_b": {"4"}},
+			want:   "GET&https%3A%2F%2Fexample.com%2Fnotify&a%3D1%26a-b%3D2%26a.b%3D3%26a_b%3D4",
+		},
+		{
+			name:   "values of a name are sorted",
+			method: "get",
+			url:    "http://example.com/",
+			params: url.Values{"x": {"b", "a c", "a"}},
+			want:   "GET&http%3A%2F%2Fexample.com%2F&x%3Da%26x%3Da%2520c%26x%3Db",
+		},
+		{
+			name:   "the scheme and host are lower cased, default ports dropped",
+			method: "GET",
+			url:    "HTTPS://Example.COM:443/Path",
+			params: url.Values{},
+			want:   "GET&https%3A%2F%2Fexample.com%2FPath&",
+		},
+		{
+			name:   "other ports are kept, oauth_signature is left out",
+			method: "GET",
+			url:    "http://example.com:8080",
+			params: url.Values{"oauth_signature": {"x"}, "k": {"v"}},
+			want:   "GET&http%3A%2F%2Fexample.com%3A8080%2F&k%3Dv",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SignatureBase(tt.method, tt.url, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("SignatureBase() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignatureBaseRelativeURL(t *testing.T) {
+	if _, err := SignatureBase("GET", "/notify", url.Values{}); err == nil {
+		t.Error("SignatureBase() of a relative URL succeeded")
+	}
+}
+
+func TestSignature(t *testing.T) {
+	tests := []struct {
+		name           string
+		base           string
+		consumerSecret string
+		tokenSecret    string
+		want           string
+	}{
+		{
+			// OAuth Core 1.0 appendix A.5
+			name: "photos example",
+			base: "GET&http%3A%2F%2Fphotos.example.net%2Fphotos&file%3Dvacation.jpg%26" +
+				"oauth_consumer_key%3Ddpf43f3p2l4k3l03%26oauth_nonce%3Dkllo9940pd9333jh%26" +
+				"oauth_signature_method%3DHMAC-SHA1%26oauth_timestamp%3D1191242096%26" +
+				"oauth_token%3Dnnch734d00sl2jdk%26oauth_version%3D1.0%26size%3Doriginal",
+			consumerSecret: "kd94hf93k423kf44",
+			tokenSecret:    "pfkkdhi9sl3r4s00",
+			want:           "tR3+Ty81lMeYAr/Fid0kMTYa/WM=",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Signature(tt.base, tt.consumerSecret, tt.tokenSecret); got != tt.want {
+				t.Errorf("Signature() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	const rawURL = "https://apps.example.com/notify?instance=a12d53dd&a-b=1&a=2"
+
+	signed, err := NewSigner("key", "secret").SignURL("POST", rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := u.Query()
+	tampered.Set("instance", "other")
+
+	unsigned := u.Query()
+	unsigned.Del("oauth_signature")
+
+	tests := []struct {
+		name     string
+		verifier *Verifier
+		params   url.Values
+		want     error
+	}{
+		{name: "valid", verifier: NewVerifier("key", "secret"), params: u.Query()},
+		{name: "wrong secret", verifier: NewVerifier("key", "other"), params: u.Query(), want: ErrInvalidSignature},
+		{name: "tampered", verifier: NewVerifier("key", "secret"), params: tampered, want: ErrInvalidSignature},
+		{name: "unsigned", verifier: NewVerifier("key", "secret"), params: unsigned, want: ErrMissingSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.verifier.Verify("POST", signed, tt.params); err != tt.want {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyReplay(t *testing.T) {
+	signed, err := NewSigner("key", "secret").SignURL("GET", "https://apps.example.com/create")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewVerifier("key", "secret")
+	if err := v.Verify("GET", signed, u.Query()); err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+	if err := v.Verify("GET", signed, u.Query()); err != ErrReplayedNonce {
+		t.Errorf("Verify() of a replayed request = %v, want %v", err, ErrReplayedNonce)
+	}
+
+	// captured requests are checked without the nonce
+	v.MaxSkew = 0
+	if err := v.Verify("GET", signed, u.Query()); err != nil {
+		t.Errorf("Verify() without MaxSkew = %v", err)
+	}
+}
@@ -7,6 +7,11 @@ import (
 
 	"github.com/elqx/eloqua-go/eloqua/bulk"
 	"github.com/elqx/eloqua-go/eloqua/rest"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/fieldsets"
+	"github.com/elqx/eloquactl/pkg/restapi"
+	"github.com/elqx/eloquactl/pkg/simulator"
 )
 
 type NdjPrinter struct{}
@@ -43,6 +48,58 @@ func (p *NdjPrinter) PrintResource(r interface{}, w io.Writer) error {
 		for _, item := range r {
 			printItem(&w, item)
 		}
+	case []bulk.Export:
+		for _, item := range r {
+			printItem(&w, item)
+		}
+	case []bulk.Sync:
+		for _, item := range r {
+			printItem(&w, item)
+		}
+	case []bulkapi.SyncLog:
+		for _, item := range r {
+			printItem(&w, item)
+		}
+	case []bulkapi.SyncReject:
+		for _, item := range r {
+			printItem(&w, item)
+		}
+	case []fieldsets.FieldSet:
+		for _, item := range r {
+			printItem(&w, item)
+		}
+	case []bulkapi.ImportResult:
+		for _, item := range r {
+			printItem(&w, item)
+		}
+	case []cache.Entry:
+		for _, item := range r {
+			printItem(&w, item)
+		}
+	case []restapi.App:
+		for _, item := range r {
+			printItem(&w, item)
+		}
+	case []restapi.Service:
+		for _, item := range r {
+			printItem(&w, item)
+		}
+	case []restapi.Contact:
+		for _, item := range r {
+			printItem(&w, item)
+		}
+	case []restapi.Account:
+		for _, item := range r {
+			printItem(&w, item)
+		}
+	case []restapi.AccountGroup:
+		for _, item := range r {
+			printItem(&w, item)
+		}
+	case []simulator.Result:
+		for _, item := range r {
+			printItem(&w, item)
+		}
 	}
 
 	return nil
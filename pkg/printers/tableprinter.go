@@ -4,10 +4,18 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/elqx/eloqua-go/eloqua/bulk"
 	"github.com/elqx/eloqua-go/eloqua/rest"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/cache"
+	"github.com/elqx/eloquactl/pkg/fieldsets"
+	"github.com/elqx/eloquactl/pkg/restapi"
+	"github.com/elqx/eloquactl/pkg/simulator"
 )
 
 type TablePrinter struct{}
@@ -117,10 +125,199 @@ func (p *TablePrinter) PrintResource(r interface{}, w io.Writer) error {
 
 			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t\n", c.Id, c.Name, c.CurrentStatus, c.CreatedAt, c.CreatedBy, c.UpdatedAt, c.UpdatedBy)
 		}
+	case []bulk.Export:
+		fields := []string{"uri", "name", "createdAt", "createdBy", "updatedAt", "updatedBy"}
+		for i, e := range r {
+			if i == 0 {
+				for _, field := range fields {
+					headers = append(headers, strings.ToUpper(field))
+				}
+				printHeader(headers, w)
+			}
+
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t\n", e.Uri, e.Name, e.CreatedAt, e.CreatedBy, e.UpdatedAt, e.UpdatedBy)
+		}
+	case []bulk.Sync:
+		fields := []string{"uri", "status", "syncedInstanceUri", "createdAt", "createdBy"}
+		for i, c := range r {
+			if i == 0 {
+				for _, field := range fields {
+					headers = append(headers, strings.ToUpper(field))
+				}
+				printHeader(headers, w)
+			}
+
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t\n", c.Uri, c.Status, c.SyncedInstanceURI, c.CreatedAt, c.CreatedBy)
+		}
+	case []bulkapi.SyncLog:
+		fields := []string{"severity", "count", "statusCode", "message", "createdAt"}
+		for i, l := range r {
+			if i == 0 {
+				for _, field := range fields {
+					headers = append(headers, strings.ToUpper(field))
+				}
+				printHeader(headers, w)
+			}
+
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t\n", l.Severity, l.Count, l.StatusCode, l.Message, l.CreatedAt)
+		}
+	case []bulkapi.SyncReject:
+		fields := []string{"recordIndex", "statusCode", "message", "invalidFields", "fieldValues"}
+		for i, rj := range r {
+			if i == 0 {
+				for _, field := range fields {
+					headers = append(headers, strings.ToUpper(field))
+				}
+				printHeader(headers, w)
+			}
+
+			values := make([]string, 0, len(rj.FieldValues))
+			for k, v := range rj.FieldValues {
+				values = append(values, k+"="+v)
+			}
+			sort.Strings(values)
+
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t\n", rj.RecordIndex, rj.StatusCode, rj.Message, strings.Join(rj.InvalidFields, ","), strings.Join(values, " "))
+		}
+	case []fieldsets.FieldSet:
+		fields := []string{"name", "entity", "source", "fields"}
+		for i, fs := range r {
+			if i == 0 {
+				for _, field := range fields {
+					headers = append(headers, strings.ToUpper(field))
+				}
+				printHeader(headers, w)
+			}
+
+			names := make([]string, 0, len(fs.Fields))
+			for name := range fs.Fields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t\n", fs.Name, fs.Entity, fs.Source, strings.Join(names, ","))
+		}
+	case []bulkapi.ImportResult:
+		fields := []string{"row", "identifier", "status", "message"}
+		for i, ir := range r {
+			if i == 0 {
+				for _, field := range fields {
+					headers = append(headers, strings.ToUpper(field))
+				}
+				printHeader(headers, w)
+			}
+
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t\n", ir.Row, ir.Identifier, ir.Status, ir.Message)
+		}
+	case []cache.Entry:
+		fields := []string{"key", "storedAt", "age", "expired", "size"}
+		for i, e := range r {
+			if i == 0 {
+				for _, field := range fields {
+					headers = append(headers, strings.ToUpper(field))
+				}
+				printHeader(headers, w)
+			}
+
+			age := time.Since(e.StoredAt).Round(time.Second)
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t\n", e.Key, e.StoredAt.Format(time.RFC3339), age, e.Expired, e.Size)
+		}
+	case []restapi.App:
+		fields := []string{"id", "name", "description", "enableUrl"}
+		for i, a := range r {
+			if i == 0 {
+				for _, field := range fields {
+					headers = append(headers, strings.ToUpper(field))
+				}
+				printHeader(headers, w)
+			}
+
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t\n", a.ID, a.Name, a.Description, a.EnableURL)
+		}
+	case []restapi.Service:
+		fields := []string{"id", "app", "type", "name", "notificationUrl"}
+		for i, s := range r {
+			if i == 0 {
+				for _, field := range fields {
+					headers = append(headers, strings.ToUpper(field))
+				}
+				printHeader(headers, w)
+			}
+
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t\n", s.ID, s.App, s.Type, s.Name, s.NotificationURL)
+		}
+	case []restapi.Contact:
+		fields := []string{"id", "emailAddress", "firstName", "lastName", "accountName", "country", "updatedAt"}
+		for i, c := range r {
+			if i == 0 {
+				for _, field := range fields {
+					headers = append(headers, strings.ToUpper(field))
+				}
+				printHeader(headers, w)
+			}
+
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t\n", c.ID, c.EmailAddress, c.FirstName, c.LastName, c.AccountName, c.Country, c.UpdatedAt)
+		}
+	case []restapi.Account:
+		fields := []string{"id", "name", "city", "country", "contacts", "updatedAt"}
+		for i, a := range r {
+			if i == 0 {
+				for _, field := range fields {
+					headers = append(headers, strings.ToUpper(field))
+				}
+				printHeader(headers, w)
+			}
+
+			contacts := "-"
+			if a.Contacts != nil {
+				contacts = strconv.Itoa(*a.Contacts)
+			}
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t\n", a.ID, a.Name, a.City, a.Country, contacts, a.UpdatedAt)
+		}
+	case []restapi.AccountGroup:
+		fields := []string{"id", "name", "description", "updatedAt"}
+		for i, g := range r {
+			if i == 0 {
+				for _, field := range fields {
+					headers = append(headers, strings.ToUpper(field))
+				}
+				printHeader(headers, w)
+			}
+
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t\n", g.ID, g.Name, summary(g.Description, 60), g.UpdatedAt)
+		}
+	case []simulator.Result:
+		fields := []string{"offset", "count", "status", "duration", "response"}
+		for i, sr := range r {
+			if i == 0 {
+				for _, field := range fields {
+					headers = append(headers, strings.ToUpper(field))
+				}
+				printHeader(headers, w)
+			}
+
+			status, response := strconv.Itoa(sr.StatusCode), sr.Body
+			if sr.Error != "" {
+				status, response = "-", sr.Error
+			}
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t\n", sr.Offset, sr.Count, status, sr.Duration, summary(response, 60))
+		}
 	}
 	return nil
 }
 
+// summary returns the first line of s, cut to n characters.
+func summary(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i] + "..."
+	}
+	if r := []rune(s); len(r) > n {
+		s = string(r[:n]) + "..."
+	}
+	return s
+}
+
 func printHeader(columnNames []string, w io.Writer) error {
 	if _, err := fmt.Fprintf(w, "%s\n", strings.Join(columnNames, "\t")); err != nil {
 		return err
@@ -0,0 +1,124 @@
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/elqx/eloqua-go/eloqua/rest"
+)
+
+// Account is an account of the REST API, a company contacts are linked to.
+// FieldValues are returned at complete depth only.
+type Account struct {
+	Type          string       `json:"type,omitempty"`
+	ID            string       `json:"id"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description,omitempty"`
+	Depth         string       `json:"depth,omitempty"`
+	CreatedAt     string       `json:"createdAt,omitempty"`
+	UpdatedAt     string       `json:"updatedAt,omitempty"`
+	Address1      string       `json:"address1,omitempty"`
+	Address2      string       `json:"address2,omitempty"`
+	Address3      string       `json:"address3,omitempty"`
+	City          string       `json:"city,omitempty"`
+	Province      string       `json:"province,omitempty"`
+	PostalCode    string       `json:"postalCode,omitempty"`
+	Country       string       `json:"country,omitempty"`
+	BusinessPhone string       `json:"businessPhone,omitempty"`
+	FieldValues   []FieldValue `json:"fieldValues,omitempty"`
+
+	// Fields are the non-empty FieldValues by field name, see SetFields.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Contacts is the number of contacts linked to the account, when counted.
+	Contacts *int `json:"contacts,omitempty"`
+}
+
+// AccountGroup is a group of accounts.
+type AccountGroup struct {
+	Type        string `json:"type,omitempty"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Depth       string `json:"depth,omitempty"`
+	CreatedAt   string `json:"createdAt,omitempty"`
+	CreatedBy   string `json:"createdBy,omitempty"`
+	UpdatedAt   string `json:"updatedAt,omitempty"`
+	UpdatedBy   string `json:"updatedBy,omitempty"`
+}
+
+type AccountList struct {
+	Elements []Account `json:"elements"`
+	rest.PageSummary
+}
+
+type AccountGroupList struct {
+	Elements []AccountGroup `json:"elements"`
+	rest.PageSummary
+}
+
+// ListAccounts returns a page of the accounts matching the search of opt.
+func ListAccounts(ctx context.Context, client *rest.RestClient, opt *rest.GetOptions) (*AccountList, error) {
+	r := &AccountList{}
+	if err := get(ctx, client, "/data/accounts", opt, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetAccount returns an account by id at the given depth.
+func GetAccount(ctx context.Context, client *rest.RestClient, id, depth string) (*Account, error) {
+	r := &Account{}
+	if err := get(ctx, client, "/data/account/"+url.PathEscape(id), &rest.GetOptions{Depth: depth}, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ListAccountGroups returns a page of the account groups matching the search of opt.
+func ListAccountGroups(ctx context.Context, client *rest.RestClient, opt *rest.GetOptions) (*AccountGroupList, error) {
+	r := &AccountGroupList{}
+	if err := get(ctx, client, "/assets/account/groups", opt, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetAccountGroup returns an account group by id at the given depth.
+func GetAccountGroup(ctx context.Context, client *rest.RestClient, id, depth string) (*AccountGroup, error) {
+	r := &AccountGroup{}
+	if err := get(ctx, client, "/assets/account/group/"+url.PathEscape(id), &rest.GetOptions{Depth: depth}, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ListAccountGroupMembers returns a page of the accounts in a group.
+func ListAccountGroupMembers(ctx context.Context, client *rest.RestClient, groupID string, opt *rest.GetOptions) (*AccountList, error) {
+	r := &AccountList{}
+	if err := get(ctx, client, "/data/accounts/group/"+url.PathEscape(groupID), opt, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// CountAccountContacts returns the number of contacts linked to an account,
+// the total of a search of the contacts by accountId, the field of Contact
+// holding the id of its account. Only the first contact is listed.
+func CountAccountContacts(ctx context.Context, client *rest.RestClient, accountID string) (int, error) {
+	r, err := ListContacts(ctx, client, &rest.GetOptions{
+		Count:  1,
+		Depth:  "minimal",
+		Search: fmt.Sprintf("accountId=%s", accountID),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return r.Total, nil
+}
+
+// SetFields sets Fields from the field values, names mapping field ids to
+// the names of the fields. Fields missing from names are keyed by id.
+func (a *Account) SetFields(names map[string]string) {
+	a.Fields = fieldsByName(a.FieldValues, names)
+}
@@ -0,0 +1,197 @@
+// Package restapi implements the REST and App Cloud API endpoints that are
+// missing from eloqua-go.
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/elqx/eloqua-go/eloqua/rest"
+)
+
+const (
+	cloudPageSize = 1000
+)
+
+// App Cloud service types.
+var ServiceTypes = []string{"feeder", "decision", "action", "content", "menu", "firehose"}
+
+// App is an App Cloud app registered in the instance.
+type App struct {
+	ID           string `json:"id,omitempty" yaml:"id,omitempty"`
+	Name         string `json:"name" yaml:"name"`
+	Description  string `json:"description,omitempty" yaml:"description,omitempty"`
+	IconURL      string `json:"iconUrl,omitempty" yaml:"iconUrl,omitempty"`
+	EnableURL    string `json:"enableUrl,omitempty" yaml:"enableUrl,omitempty"`
+	StatusURL    string `json:"statusUrl,omitempty" yaml:"statusUrl,omitempty"`
+	CallbackURL  string `json:"callbackUrl,omitempty" yaml:"callbackUrl,omitempty"`
+	UninstallURL string `json:"uninstallUrl,omitempty" yaml:"uninstallUrl,omitempty"`
+}
+
+// Service is a service of an App Cloud app. Fields not used by its type are
+// left empty, e.g. Events is for firehose services only.
+type Service struct {
+	ID    string `json:"id,omitempty" yaml:"id,omitempty"`
+	AppID string `json:"appId,omitempty" yaml:"-"`
+	// App is the name of the app, in manifests.
+	App         string `json:"-" yaml:"app"`
+	Type        string `json:"type" yaml:"type"`
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	IconURL     string `json:"iconUrl,omitempty" yaml:"iconUrl,omitempty"`
+
+	CreateURL       string `json:"createUrl,omitempty" yaml:"createUrl,omitempty"`
+	ConfigureURL    string `json:"configureUrl,omitempty" yaml:"configureUrl,omitempty"`
+	NotificationURL string `json:"notificationUrl,omitempty" yaml:"notificationUrl,omitempty"`
+	DeleteURL       string `json:"deleteUrl,omitempty" yaml:"deleteUrl,omitempty"`
+	CopyURL         string `json:"copyUrl,omitempty" yaml:"copyUrl,omitempty"`
+
+	MaxRecordsPerNotification int `json:"maxRecordsPerNotification,omitempty" yaml:"maxRecordsPerNotification,omitempty"`
+
+	// content services
+	Height         int    `json:"height,omitempty" yaml:"height,omitempty"`
+	Width          int    `json:"width,omitempty" yaml:"width,omitempty"`
+	EditorImageURL string `json:"editorImageUrl,omitempty" yaml:"editorImageUrl,omitempty"`
+
+	// menu services
+	ActionURL string   `json:"actionUrl,omitempty" yaml:"actionUrl,omitempty"`
+	Layout    string   `json:"layout,omitempty" yaml:"layout,omitempty"`
+	Areas     []string `json:"areas,omitempty" yaml:"areas,omitempty"`
+
+	// firehose services
+	Events []string `json:"events,omitempty" yaml:"events,omitempty"`
+}
+
+type appList struct {
+	Elements []App `json:"elements"`
+	rest.PageSummary
+}
+
+type serviceList struct {
+	Elements []Service `json:"elements"`
+	rest.PageSummary
+}
+
+// ListApps returns the App Cloud apps of the instance.
+func ListApps(ctx context.Context, client *rest.RestClient) ([]App, error) {
+	var apps []App
+	for page := 1; ; page++ {
+		r := &appList{}
+		if err := cloudDo(ctx, client, "GET", "/apps?"+pageQuery(page), nil, r); err != nil {
+			return nil, err
+		}
+
+		apps = append(apps, r.Elements...)
+		if len(r.Elements) == 0 || page*cloudPageSize >= r.Total {
+			return apps, nil
+		}
+	}
+}
+
+func CreateApp(ctx context.Context, client *rest.RestClient, app *App) (*App, error) {
+	r := &App{}
+	if err := cloudDo(ctx, client, "POST", "/apps", app, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func UpdateApp(ctx context.Context, client *rest.RestClient, app *App) (*App, error) {
+	r := &App{}
+	if err := cloudDo(ctx, client, "PUT", "/apps/"+url.PathEscape(app.ID), app, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func DeleteApp(ctx context.Context, client *rest.RestClient, id string) error {
+	return cloudDo(ctx, client, "DELETE", "/apps/"+url.PathEscape(id), nil, nil)
+}
+
+// ListServices returns the services of an app. Their App is left empty.
+func ListServices(ctx context.Context, client *rest.RestClient, appID string) ([]Service, error) {
+	var services []Service
+	for page := 1; ; page++ {
+		r := &serviceList{}
+		if err := cloudDo(ctx, client, "GET", servicesPath(appID)+"?"+pageQuery(page), nil, r); err != nil {
+			return nil, err
+		}
+
+		for _, s := range r.Elements {
+			s.AppID = appID
+			services = append(services, s)
+		}
+		if len(r.Elements) == 0 || page*cloudPageSize >= r.Total {
+			return services, nil
+		}
+	}
+}
+
+func CreateService(ctx context.Context, client *rest.RestClient, s *Service) (*Service, error) {
+	r := &Service{}
+	if err := cloudDo(ctx, client, "POST", servicesPath(s.AppID), s, r); err != nil {
+		return nil, err
+	}
+	r.AppID = s.AppID
+	return r, nil
+}
+
+func UpdateService(ctx context.Context, client *rest.RestClient, s *Service) (*Service, error) {
+	r := &Service{}
+	if err := cloudDo(ctx, client, "PUT", servicesPath(s.AppID)+"/"+url.PathEscape(s.ID), s, r); err != nil {
+		return nil, err
+	}
+	r.AppID = s.AppID
+	return r, nil
+}
+
+func DeleteService(ctx context.Context, client *rest.RestClient, appID, id string) error {
+	return cloudDo(ctx, client, "DELETE", servicesPath(appID)+"/"+url.PathEscape(id), nil, nil)
+}
+
+func servicesPath(appID string) string {
+	return "/apps/" + url.PathEscape(appID) + "/services"
+}
+
+func pageQuery(page int) string {
+	v := url.Values{}
+	v.Set("count", strconv.Itoa(cloudPageSize))
+	v.Set("page", strconv.Itoa(page))
+	return v.Encode()
+}
+
+// CloudURL returns the URL of a path of the App Cloud API, e.g. /apps,
+// which is served next to the REST API of the client, under /api/cloud/1.0.
+func CloudURL(client *rest.RestClient, path string) string {
+	u := *client.BaseURL
+	if i := strings.Index(strings.ToLower(u.Path), "/api/"); i >= 0 {
+		u.Path = u.Path[:i]
+	} else {
+		u.Path = strings.TrimSuffix(u.Path, "/")
+	}
+	u.RawQuery = ""
+
+	return u.String() + "/api/cloud/1.0" + path
+}
+
+// cloudDo sends a request to the App Cloud API and decodes the response into v.
+func cloudDo(ctx context.Context, client *rest.RestClient, method, path string, body, v interface{}) error {
+	// NewRequest drops the first character of the URL, the leading slash of
+	// paths, an absolute URL is then used as is
+	req, err := client.NewRequest(method, "/"+CloudURL(client, path), nil, body)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		var discard interface{}
+		v = &discard
+	}
+	if _, err := client.Do(ctx, req, v); err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package restapi
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/elqx/eloqua-go/eloqua/rest"
+)
+
+// Contact is a contact of the REST API. FieldValues, the values of all
+// contact fields by field id, are returned at complete depth only.
+type Contact struct {
+	Type             string       `json:"type,omitempty"`
+	ID               string       `json:"id"`
+	Name             string       `json:"name,omitempty"`
+	Depth            string       `json:"depth,omitempty"`
+	CreatedAt        string       `json:"createdAt,omitempty"`
+	UpdatedAt        string       `json:"updatedAt,omitempty"`
+	EmailAddress     string       `json:"emailAddress,omitempty"`
+	FirstName        string       `json:"firstName,omitempty"`
+	LastName         string       `json:"lastName,omitempty"`
+	Title            string       `json:"title,omitempty"`
+	AccountID        string       `json:"accountId,omitempty"`
+	AccountName      string       `json:"accountName,omitempty"`
+	BusinessPhone    string       `json:"businessPhone,omitempty"`
+	MobilePhone      string       `json:"mobilePhone,omitempty"`
+	Address1         string       `json:"address1,omitempty"`
+	City             string       `json:"city,omitempty"`
+	Province         string       `json:"province,omitempty"`
+	PostalCode       string       `json:"postalCode,omitempty"`
+	Country          string       `json:"country,omitempty"`
+	SalesPerson      string       `json:"salesPerson,omitempty"`
+	IsSubscribed     string       `json:"isSubscribed,omitempty"`
+	IsBounceback     string       `json:"isBounceback,omitempty"`
+	SubscriptionDate string       `json:"subscriptionDate,omitempty"`
+	FieldValues      []FieldValue `json:"fieldValues,omitempty"`
+
+	// Fields are the non-empty FieldValues by field name, see SetFields.
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// FieldValue is the value of a field of a contact or account.
+type FieldValue struct {
+	Type  string `json:"type,omitempty"`
+	ID    string `json:"id"`
+	Value string `json:"value,omitempty"`
+}
+
+type ContactList struct {
+	Elements []Contact `json:"elements"`
+	rest.PageSummary
+}
+
+// ListContacts returns a page of the contacts matching the search of opt.
+func ListContacts(ctx context.Context, client *rest.RestClient, opt *rest.GetOptions) (*ContactList, error) {
+	r := &ContactList{}
+	if err := get(ctx, client, "/data/contacts", opt, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetContact returns a contact by id at the given depth.
+func GetContact(ctx context.Context, client *rest.RestClient, id, depth string) (*Contact, error) {
+	r := &Contact{}
+	if err := get(ctx, client, "/data/contact/"+url.PathEscape(id), &rest.GetOptions{Depth: depth}, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// SetFields sets Fields from the field values, names mapping field ids to
+// the names of the fields. Fields missing from names are keyed by id.
+func (c *Contact) SetFields(names map[string]string) {
+	c.Fields = fieldsByName(c.FieldValues, names)
+}
+
+func fieldsByName(values []FieldValue, names map[string]string) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	fields := map[string]string{}
+	for _, v := range values {
+		if v.Value == "" {
+			continue
+		}
+		name, ok := names[v.ID]
+		if !ok {
+			name = v.ID
+		}
+		fields[name] = v.Value
+	}
+	return fields
+}
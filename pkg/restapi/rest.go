@@ -0,0 +1,31 @@
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/elqx/eloqua-go/eloqua/rest"
+)
+
+// get sends a GET request to a path of the REST API, e.g. /data/contacts,
+// and decodes the response into v.
+func get(ctx context.Context, client *rest.RestClient, path string, opt interface{}, v interface{}) error {
+	req, err := client.NewRequest("GET", path, opt, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.Do(ctx, req, v); err != nil {
+		return fmt.Errorf("GET %s: %w", path, err)
+	}
+	return nil
+}
+
+// SearchTerm returns the search of the REST API for field equal to value,
+// e.g. name='Acme'. The search syntax has no escape for quotes, they are
+// searched as the * wildcard instead, so the results are to be narrowed
+// down to the value. Wildcards of the value match too.
+func SearchTerm(field, value string) string {
+	return fmt.Sprintf("%s='%s'", field, strings.Replace(value, "'", "*", -1))
+}
@@ -0,0 +1,159 @@
+// Package simulator sends the calls Eloqua makes to App Cloud services, to
+// test services without a campaign.
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elqx/eloquactl/pkg/oauth1"
+)
+
+const (
+	// DefaultBatchSize is the number of records Eloqua sends per notify
+	// call, unless the service sets maxRecordsPerNotification.
+	DefaultBatchSize = 1000
+	MaxBatchSize     = 5000
+)
+
+// Batch is the body of a notify call, a page of the records of an execution.
+type Batch struct {
+	Offset       int                 `json:"offset"`
+	Limit        int                 `json:"limit"`
+	TotalResults int                 `json:"totalResults"`
+	Count        int                 `json:"count"`
+	HasMore      bool                `json:"hasMore"`
+	Items        []map[string]string `json:"items"`
+}
+
+// Batches pages the records as Eloqua does, limit records per batch.
+func Batches(records []map[string]string, limit int) []Batch {
+	var batches []Batch
+	for offset := 0; offset < len(records); offset += limit {
+		end := offset + limit
+		if end > len(records) {
+			end = len(records)
+		}
+
+		batches = append(batches, Batch{
+			Offset:       offset,
+			Limit:        limit,
+			TotalResults: len(records),
+			Count:        end - offset,
+			HasMore:      end < len(records),
+			Items:        records[offset:end],
+		})
+	}
+	return batches
+}
+
+// Result is the response of a service to a simulated notify call.
+type Result struct {
+	Offset      int    `json:"offset"`
+	Count       int    `json:"count"`
+	StatusCode  int    `json:"statusCode,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Duration    string `json:"duration"`
+	Body        string `json:"body,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Failed tells whether the call failed or the service did not accept it.
+func (r *Result) Failed() bool {
+	return r.Error != "" || r.StatusCode < 200 || r.StatusCode > 299
+}
+
+// Simulator sends notify calls to a service as Eloqua does.
+type Simulator struct {
+	// URL is the notification URL of the service. Its {InstanceId} and
+	// {ExecutionId} templates are replaced, the instance and execution
+	// query parameters are added when there are none.
+	URL       string
+	Instance  string
+	Execution int
+	// Signer signs the calls, which are not signed when it is nil.
+	Signer *oauth1.Signer
+	Client *http.Client
+}
+
+// NotifyURL returns the URL the batches are posted to.
+func (s *Simulator) NotifyURL() (string, error) {
+	execution := strconv.Itoa(s.Execution)
+	templated := strings.Contains(s.URL, "{InstanceId}") || strings.Contains(s.URL, "{ExecutionId}")
+	raw := strings.NewReplacer("{InstanceId}", s.Instance, "{ExecutionId}", execution).Replace(s.URL)
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	if !templated {
+		q := u.Query()
+		if q.Get("instance") == "" {
+			q.Set("instance", s.Instance)
+		}
+		if q.Get("execution") == "" {
+			q.Set("execution", execution)
+		}
+		u.RawQuery = q.Encode()
+	}
+	return u.String(), nil
+}
+
+// Notify posts a batch. Errors of the call are in the result, only invalid
+// URLs and signing errors are returned.
+func (s *Simulator) Notify(ctx context.Context, b *Batch) (*Result, error) {
+	notifyURL, err := s.NotifyURL()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", notifyURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	if s.Signer != nil {
+		if err := s.Signer.SignRequest(req); err != nil {
+			return nil, err
+		}
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	r := &Result{Offset: b.Offset, Count: b.Count}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		r.Duration = time.Since(start).Round(time.Millisecond).String()
+		r.Error = err.Error()
+		return r, nil
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	r.Duration = time.Since(start).Round(time.Millisecond).String()
+	r.StatusCode = resp.StatusCode
+	r.ContentType = resp.Header.Get("Content-Type")
+	r.Body = string(data)
+	if err != nil {
+		r.Error = err.Error()
+	}
+	return r, nil
+}
@@ -0,0 +1,129 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxConcurrent is the number of requests eloquactl sends at once to
+	// an Eloqua instance, so it stays within the instance's concurrency limit.
+	DefaultMaxConcurrent = 10
+)
+
+// Limiter bounds the number of concurrent requests and the request rate.
+// A single Limiter should be shared by all the clients talking to one instance.
+type Limiter struct {
+	sem chan struct{}
+
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewLimiter returns a Limiter allowing maxConcurrent requests in flight and
+// at most rate requests per second. Zero values disable the respective limit.
+func NewLimiter(maxConcurrent int, rate float64) *Limiter {
+	l := &Limiter{}
+	if maxConcurrent > 0 {
+		l.sem = make(chan struct{}, maxConcurrent)
+	}
+	if rate > 0 {
+		l.interval = time.Duration(float64(time.Second) / rate)
+	}
+	return l
+}
+
+// acquire blocks until the request may be sent or the request is canceled.
+func (l *Limiter) acquire(req *http.Request) error {
+	if l.sem != nil {
+		select {
+		case l.sem <- struct{}{}:
+		case <-req.Context().Done():
+			return req.Context().Err()
+		}
+	}
+
+	if wait := l.reserve(); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			l.release()
+			return req.Context().Err()
+		}
+	}
+	return nil
+}
+
+// reserve returns how long the caller has to wait for its slot in the rate limit.
+func (l *Limiter) reserve() time.Duration {
+	if l.interval == 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	return wait
+}
+
+func (l *Limiter) release() {
+	if l.sem != nil {
+		<-l.sem
+	}
+}
+
+// LimitTransport sends requests through a Limiter. A request keeps its slot
+// until its response body is closed, so that downloads count as in flight.
+type LimitTransport struct {
+	Limiter *Limiter
+
+	// Transport is the underlying HTTP transport to use when making requests
+	Transport http.RoundTripper
+}
+
+// RoundTrip implements the RoundTripper interface
+func (t *LimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.Limiter.acquire(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.transport().RoundTrip(req)
+	if err != nil {
+		t.Limiter.release()
+		return nil, err
+	}
+	resp.Body = &closeHook{ReadCloser: resp.Body, hook: t.Limiter.release}
+	return resp, nil
+}
+
+func (t *LimitTransport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return http.DefaultTransport
+}
+
+// closeHook calls hook once, when the body is closed.
+type closeHook struct {
+	io.ReadCloser
+
+	once sync.Once
+	hook func()
+}
+
+func (b *closeHook) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.hook)
+	return err
+}
@@ -0,0 +1,146 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// roundTripFunc is a RoundTripper answering without a server.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func okTransport() http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader("ok"))}, nil
+	})
+}
+
+// roundTrip sends a GET that gives up after timeout.
+func roundTrip(rt http.RoundTripper, timeout time.Duration) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	return rt.RoundTrip(req.WithContext(ctx))
+}
+
+func TestLimitTransportHoldsSlotUntilClose(t *testing.T) {
+	rt := &LimitTransport{Limiter: NewLimiter(1, 0), Transport: okTransport()}
+
+	first, err := roundTrip(rt, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := roundTrip(rt, 50*time.Millisecond); err != context.DeadlineExceeded {
+		t.Fatalf("request while the body is open = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	first.Body.Close()
+	// closing twice releases the slot once
+	first.Body.Close()
+
+	second, err := roundTrip(rt, time.Second)
+	if err != nil {
+		t.Fatalf("request after the body is closed = %v", err)
+	}
+	second.Body.Close()
+}
+
+func TestLimitTransportReleasesOnError(t *testing.T) {
+	failed := errors.New("connection refused")
+	calls := 0
+	rt := &LimitTransport{
+		Limiter: NewLimiter(1, 0),
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return nil, failed
+		}),
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := roundTrip(rt, 50*time.Millisecond); err != failed {
+			t.Fatalf("request %d = %v, want %v", i, err, failed)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("%d requests sent, want 3", calls)
+	}
+}
+
+func TestLimitTransportConcurrency(t *testing.T) {
+	const maxConcurrent = 3
+
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &LimitTransport{Limiter: NewLimiter(maxConcurrent, 0)}}
+
+	done := make(chan error)
+	for i := 0; i < 10; i++ {
+		go func() {
+			resp, err := client.Get(srv.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			done <- err
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if peak > maxConcurrent {
+		t.Errorf("%d requests in flight, want at most %d", peak, maxConcurrent)
+	}
+}
+
+func TestLimiterReserve(t *testing.T) {
+	tests := []struct {
+		name string
+		rate float64
+		want []time.Duration
+	}{
+		{name: "no rate limit", rate: 0, want: []time.Duration{0, 0, 0}},
+		{name: "10 per second", rate: 10, want: []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond}},
+		{name: "2 per second", rate: 2, want: []time.Duration{0, 500 * time.Millisecond, time.Second}},
+	}
+
+	const slack = 20 * time.Millisecond
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLimiter(0, tt.rate)
+			for i, want := range tt.want {
+				if got := l.reserve(); got > want || got < want-slack {
+					t.Errorf("reserve() #%d = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
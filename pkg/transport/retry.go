@@ -0,0 +1,173 @@
+// Package transport provides http.RoundTripper middlewares shared by all API
+// clients: retries with backoff and client side rate limiting.
+package transport
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultMaxRetries = 5
+	DefaultMinBackoff = 1 * time.Second
+	DefaultMaxBackoff = 1 * time.Minute
+
+	// maxRetryAfter caps the delay requested by the server with Retry-After.
+	maxRetryAfter = 10 * time.Minute
+)
+
+// RetryTransport retries idempotent requests that failed with a network error
+// or a status code meaning the server is overloaded or temporarily unavailable.
+// The delay between attempts grows exponentially, with full jitter, unless the
+// server tells how long to wait with the Retry-After header.
+type RetryTransport struct {
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Timeout limits every attempt, reading the response body included, but
+	// not the delays between attempts. Zero means no timeout.
+	Timeout time.Duration
+
+	// Transport is the underlying HTTP transport to use when making requests
+	Transport http.RoundTripper
+}
+
+// NewRetryTransport returns a RetryTransport with default backoff settings.
+func NewRetryTransport(base http.RoundTripper, maxRetries int) *RetryTransport {
+	return &RetryTransport{
+		MaxRetries: maxRetries,
+		MinBackoff: DefaultMinBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+		Transport:  base,
+	}
+}
+
+// RoundTrip implements the RoundTripper interface
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isIdempotent(req) {
+		return t.attempt(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.WithContext(req.Context())
+			r.Body = body
+		}
+
+		resp, err := t.attempt(r)
+		if attempt >= t.MaxRetries || !shouldRetry(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt)
+		if resp != nil {
+			if d, ok := retryAfter(resp); ok {
+				wait = d
+			}
+			// drain the body so the connection can be reused
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends the request once, within Timeout.
+func (t *RetryTransport) attempt(req *http.Request) (*http.Response, error) {
+	if t.Timeout <= 0 {
+		return t.transport().RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.Timeout)
+	resp, err := t.transport().RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &closeHook{ReadCloser: resp.Body, hook: cancel}
+	return resp, nil
+}
+
+func (t *RetryTransport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return http.DefaultTransport
+}
+
+// backoff returns a random delay between 0 and MinBackoff * 2^attempt, capped by MaxBackoff.
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	d := t.MinBackoff << uint(attempt)
+	if d <= 0 || d > t.MaxBackoff {
+		d = t.MaxBackoff
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// isIdempotent reports whether the request can be safely sent more than once.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	default:
+		return false
+	}
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header, given either in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	var d time.Duration
+	if secs, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(secs) * time.Second
+	} else if at, err := http.ParseTime(v); err == nil {
+		d = time.Until(at)
+	} else {
+		return 0, false
+	}
+
+	if d < 0 {
+		d = 0
+	}
+	if d > maxRetryAfter {
+		d = maxRetryAfter
+	}
+	return d, true
+}
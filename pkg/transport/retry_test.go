@@ -0,0 +1,190 @@
+package transport
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		statuses   []int
+		maxRetries int
+		wantCalls  int32
+		wantStatus int
+	}{
+		{name: "success", method: "GET", statuses: []int{200}, maxRetries: 3, wantCalls: 1, wantStatus: 200},
+		{name: "retried until success", method: "GET", statuses: []int{503, 429, 200}, maxRetries: 3, wantCalls: 3, wantStatus: 200},
+		{name: "retries exhausted", method: "GET", statuses: []int{502, 504, 503, 200}, maxRetries: 2, wantCalls: 3, wantStatus: 503},
+		{name: "client error not retried", method: "GET", statuses: []int{404, 200}, maxRetries: 3, wantCalls: 1, wantStatus: 404},
+		{name: "server error not retried", method: "GET", statuses: []int{500, 200}, maxRetries: 3, wantCalls: 1, wantStatus: 500},
+		{name: "POST not retried", method: "POST", body: "{}", statuses: []int{503, 200}, maxRetries: 3, wantCalls: 1, wantStatus: 503},
+		{name: "PUT retried with its body", method: "PUT", body: `{"name":"x"}`, statuses: []int{503, 200}, maxRetries: 3, wantCalls: 2, wantStatus: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&calls, 1)
+				body, _ := ioutil.ReadAll(r.Body)
+				if string(body) != tt.body {
+					t.Errorf("attempt %d sent body %q, want %q", n, body, tt.body)
+				}
+				w.WriteHeader(tt.statuses[n-1])
+			}))
+			defer srv.Close()
+
+			rt := NewRetryTransport(nil, tt.maxRetries)
+			rt.MinBackoff = time.Millisecond
+			rt.MaxBackoff = 5 * time.Millisecond
+
+			var req *http.Request
+			var err error
+			if tt.body != "" {
+				req, err = http.NewRequest(tt.method, srv.URL, strings.NewReader(tt.body))
+			} else {
+				req, err = http.NewRequest(tt.method, srv.URL, nil)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := rt.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if n := atomic.LoadInt32(&calls); n != tt.wantCalls {
+				t.Errorf("%d attempts, want %d", n, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryTransportRetryAfter(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	rt := NewRetryTransport(nil, 1)
+	rt.MinBackoff = time.Millisecond
+	rt.MaxBackoff = time.Millisecond
+
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	start := time.Now()
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, Retry-After asked for 1s", elapsed)
+	}
+}
+
+func TestRetryTransportTimeout(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// the first attempt hangs until the client gives up
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	rt := NewRetryTransport(nil, 2)
+	rt.MinBackoff = time.Millisecond
+	rt.MaxBackoff = time.Millisecond
+	rt.Timeout = 100 * time.Millisecond
+
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// the timeout of the attempt that succeeded must not cut the body short
+	time.Sleep(2 * rt.Timeout)
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil || string(body) != "ok" {
+		t.Errorf("body = %q, %v, want \"ok\"", body, err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("%d attempts, want 2", n)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "missing", header: "", wantOK: false},
+		{name: "seconds", header: "3", want: 3 * time.Second, wantOK: true},
+		{name: "negative", header: "-3", want: 0, wantOK: true},
+		{name: "capped", header: "86400", want: maxRetryAfter, wantOK: true},
+		{name: "date in the past", header: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0, wantOK: true},
+		{name: "invalid", header: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("Retry-After", tt.header)
+			}
+			got, ok := retryAfter(resp)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("retryAfter() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	rt := &RetryTransport{MinBackoff: time.Second, MaxBackoff: time.Minute}
+
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{attempt: 0, max: time.Second},
+		{attempt: 3, max: 8 * time.Second},
+		{attempt: 6, max: time.Minute},
+		{attempt: 70, max: time.Minute},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			if d := rt.backoff(tt.attempt); d < 0 || d > tt.max {
+				t.Fatalf("backoff(%d) = %v, want between 0 and %v", tt.attempt, d, tt.max)
+			}
+		}
+	}
+}
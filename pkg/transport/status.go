@@ -0,0 +1,64 @@
+package transport
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	// maxErrorBody limits how much of an error response is kept.
+	maxErrorBody = 4096
+)
+
+// APIError is returned for responses with a 4xx or 5xx status code.
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+
+	// Body is the beginning of the response body, Eloqua describes the error there.
+	Body string
+}
+
+// Error does not repeat the method and URL, http.Client adds them.
+func (e *APIError) Error() string {
+	if e.Body != "" {
+		return e.Status + ": " + e.Body
+	}
+	return e.Status
+}
+
+// StatusTransport turns error responses into an *APIError, as the API
+// clients decode any response body without looking at the status code.
+type StatusTransport struct {
+	// Transport is the underlying HTTP transport to use when making requests
+	Transport http.RoundTripper
+}
+
+// RoundTrip implements the RoundTripper interface
+func (t *StatusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport().RoundTrip(req)
+	if err != nil || resp.StatusCode < 400 {
+		return resp, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return nil, &APIError{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       strings.TrimSpace(string(body)),
+	}
+}
+
+func (t *StatusTransport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return http.DefaultTransport
+}
@@ -0,0 +1,20 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// PrintDryRun prints the definition a command would create as indented JSON
+// to stdout, so it can be saved and reviewed, and the request it would be
+// sent with to stderr.
+func PrintDryRun(method, path string, definition interface{}) error {
+	fmt.Fprintf(os.Stderr, "%s %s\n", method, path)
+
+	enc := json.NewEncoder(os.Stdout)
+	// filters are easier to review with their comparison operators as is
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	return enc.Encode(definition)
+}
@@ -0,0 +1,115 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/elqx/eloquactl/pkg/oauth"
+	"github.com/elqx/eloquactl/pkg/transport"
+)
+
+// Exit codes, documented in the README.
+const (
+	ExitError       = 1
+	ExitValidation  = 2
+	ExitAuth        = 3
+	ExitAPI         = 4
+	ExitSync        = 5
+	ExitPartial     = 6
+	ExitInterrupted = 130
+)
+
+// ValidationError is returned for invalid arguments or flags.
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string { return e.Err.Error() }
+
+// AuthError is returned when the user can not be authenticated.
+type AuthError struct {
+	Err error
+}
+
+func (e *AuthError) Error() string { return e.Err.Error() }
+
+// SyncError is returned when a Bulk API sync fails or does not finish in time.
+type SyncError struct {
+	Uri string
+	Err error
+}
+
+func (e *SyncError) Error() string {
+	return fmt.Sprintf("sync %s %v", e.Uri, e.Err)
+}
+
+// PartialError is returned when a command did only part of its work,
+// e.g. some of the records were rejected.
+type PartialError struct {
+	Err error
+}
+
+func (e *PartialError) Error() string { return e.Err.Error() }
+
+// InterruptedError is returned when the command was stopped by a signal.
+type InterruptedError struct{}
+
+func (e *InterruptedError) Error() string { return "interrupted" }
+
+// ValidationErrorf returns a ValidationError with a formatted message.
+func ValidationErrorf(format string, a ...interface{}) error {
+	return &ValidationError{Err: fmt.Errorf(format, a...)}
+}
+
+// ExitCode returns the process exit code for err. The typed errors are
+// looked for in the whole chain of err, e.g. the error returned by a
+// transport and wrapped in *url.Error by http.Client.
+func ExitCode(err error) int {
+	var (
+		interrupted *InterruptedError
+		validation  *ValidationError
+		auth        *AuthError
+		token       *oauth.TokenError
+		api         *transport.APIError
+		sync        *SyncError
+		partial     *PartialError
+	)
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &interrupted):
+		return ExitInterrupted
+	case errors.As(err, &validation):
+		return ExitValidation
+	case errors.As(err, &auth), errors.As(err, &token):
+		return ExitAuth
+	case errors.As(err, &api):
+		if api.StatusCode == http.StatusUnauthorized || api.StatusCode == http.StatusForbidden {
+			return ExitAuth
+		}
+		return ExitAPI
+	case errors.As(err, &sync):
+		return ExitSync
+	case errors.As(err, &partial):
+		return ExitPartial
+	default:
+		return ExitError
+	}
+}
+
+// IsNotFound reports whether err is an API error for a missing resource.
+func IsNotFound(err error) bool {
+	var e *transport.APIError
+	return errors.As(err, &e) && e.StatusCode == http.StatusNotFound
+}
+
+// CheckErr prints the error to stderr and exits with its exit code if err is not nil.
+func CheckErr(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(ExitCode(err))
+	}
+}
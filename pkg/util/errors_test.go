@@ -0,0 +1,41 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/elqx/eloquactl/pkg/oauth"
+	"github.com/elqx/eloquactl/pkg/transport"
+)
+
+func TestExitCode(t *testing.T) {
+	failed := errors.New("failed")
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, 0},
+		{"plain", failed, ExitError},
+		{"validation", ValidationErrorf("bad flag"), ExitValidation},
+		{"auth", &AuthError{Err: failed}, ExitAuth},
+		{"token wrapped by http.Client", &url.Error{Op: "Get", URL: "https://example.com", Err: &oauth.TokenError{Err: failed}}, ExitAuth},
+		{"unauthorized", &transport.APIError{StatusCode: 401}, ExitAuth},
+		{"forbidden", &transport.APIError{StatusCode: 403}, ExitAuth},
+		{"API wrapped", fmt.Errorf("failed to list contact fields: %w", &transport.APIError{StatusCode: 500}), ExitAPI},
+		{"sync", &SyncError{Uri: "/syncs/1", Err: failed}, ExitSync},
+		{"partial wrapped", fmt.Errorf("failed to export contacts: %w", &PartialError{Err: failed}), ExitPartial},
+		{"interrupted wrapped", fmt.Errorf("failed to export segment 1: %w", &InterruptedError{}), ExitInterrupted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
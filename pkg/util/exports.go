@@ -0,0 +1,93 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+)
+
+const (
+	// downloadBatchSize is the number of rows downloaded per request
+	downloadBatchSize = 25000
+)
+
+// RunExport syncs the export definition at uri and downloads the exported
+// data in batches, passing every batch to write, e.g. to print it. It returns
+// the number of rows written, also when it fails: batches already written are
+// kept. When the sync finishes with warnings its logs are printed, all data is
+// downloaded and a PartialError is returned. The definition is deleted when
+// the command is interrupted, unless keepDefinition is set.
+func RunExport(ctx context.Context, client *bulk.BulkClient, uri string, sf *SyncFlags, keepDefinition bool, write func([]bulk.Item) error) (rows int, err error) {
+	// the definition is useless once the command is interrupted
+	defer func() {
+		if ctx.Err() == nil {
+			return
+		}
+		if !keepDefinition {
+			deleteDefinition(client, uri, "export")
+		}
+		err = &InterruptedError{}
+	}()
+
+	sync, err := client.Syncs.Create(ctx, &bulk.Sync{SyncedInstanceURI: uri})
+	if err != nil {
+		return 0, err
+	}
+
+	syncId, err := bulkapi.SyncID(sync.Uri)
+	if err != nil {
+		return 0, err
+	}
+
+	progress := NewProgress(sync.Uri)
+	defer progress.Done()
+
+	sync, err = WaitSync(ctx, client, sync, sf, progress)
+	if err != nil {
+		return 0, err
+	}
+
+	if sync.Status != bulkapi.SyncSuccess {
+		progress.Done()
+		if err := PrintSyncLogs(ctx, client, syncId); err != nil {
+			return 0, err
+		}
+
+		if sync.Status == bulkapi.SyncError {
+			return 0, &SyncError{Uri: sync.Uri, Err: errors.New("failed")}
+		}
+	}
+
+	opt := &bulk.QueryOptions{Limit: downloadBatchSize}
+	for {
+		progress.Update("downloading", rows)
+		data, err := client.Syncs.GetData(ctx, syncId, opt)
+		if err != nil {
+			return rows, err
+		}
+
+		// end the status line so it does not mix with the data on a terminal
+		progress.Done()
+		if err := write(data.Items); err != nil {
+			return rows, err
+		}
+
+		rows += len(data.Items)
+		progress.Update("downloading", rows)
+
+		if !data.HasMore {
+			break
+		}
+		opt.Offset += downloadBatchSize
+	}
+	progress.Update("done", rows)
+
+	// the data is exported, but the logs say some of it is not right
+	if sync.Status == bulkapi.SyncWarning {
+		return rows, &PartialError{Err: fmt.Errorf("sync %s finished with warnings", sync.Uri)}
+	}
+	return rows, nil
+}
@@ -1,19 +1,17 @@
 package util
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/elqx/eloquactl/pkg/printers"
 	"github.com/spf13/cobra"
 )
 
-func Er(msg interface{}) {
-	fmt.Println("Error:", msg)
-	os.Exit(1)
-}
-
 type StagingFlags struct {
 	AutoDeleteDuration    *string
 	DataRetentionDuration *string
@@ -30,11 +28,11 @@ func NewStagingFlags() *StagingFlags {
 
 func (f *StagingFlags) AddFlags(cmd *cobra.Command) {
 	if f.AutoDeleteDuration != nil {
-		cmd.Flags().String("auto-delete-duration", *f.AutoDeleteDuration, "Time until the definition will be deleted, expressed using the ISO-8601 standard.")
+		cmd.Flags().StringVar(f.AutoDeleteDuration, "auto-delete-duration", *f.AutoDeleteDuration, "Time until the definition will be deleted, expressed using the ISO-8601 standard.")
 	}
 
 	if f.DataRetentionDuration != nil {
-		cmd.Flags().String("data-retention-duration", *f.DataRetentionDuration, "The length of time exported data should remain in the staging area., expressed using the ISO-8601 standard.")
+		cmd.Flags().StringVar(f.DataRetentionDuration, "data-retention-duration", *f.DataRetentionDuration, "The length of time exported data should remain in the staging area., expressed using the ISO-8601 standard.")
 	}
 }
 
@@ -42,6 +40,52 @@ func (f *StagingFlags) Validate() error {
 	return nil
 }
 
+type SyncFlags struct {
+	PollInterval    *time.Duration
+	MaxPollInterval *time.Duration
+	SyncTimeout     *time.Duration
+}
+
+func NewSyncFlags() *SyncFlags {
+	pollInterval := 1 * time.Second
+	maxPollInterval := 30 * time.Second
+	syncTimeout := time.Duration(0)
+	return &SyncFlags{
+		PollInterval:    &pollInterval,
+		MaxPollInterval: &maxPollInterval,
+		SyncTimeout:     &syncTimeout,
+	}
+}
+
+func (f *SyncFlags) AddFlags(cmd *cobra.Command) {
+	if f.PollInterval != nil {
+		cmd.Flags().DurationVar(f.PollInterval, "poll-interval", *f.PollInterval, "Initial interval between sync status checks. It grows up to --max-poll-interval while the sync is running.")
+	}
+
+	if f.MaxPollInterval != nil {
+		cmd.Flags().DurationVar(f.MaxPollInterval, "max-poll-interval", *f.MaxPollInterval, "Maximum interval between sync status checks.")
+	}
+
+	if f.SyncTimeout != nil {
+		cmd.Flags().DurationVar(f.SyncTimeout, "sync-timeout", *f.SyncTimeout, "The time to wait for the sync to finish, e.g. 30m. Zero means wait forever.")
+	}
+}
+
+func (f *SyncFlags) Validate() error {
+	if *f.PollInterval <= 0 {
+		return errors.New("--poll-interval must be greater than zero")
+	}
+
+	if *f.MaxPollInterval < *f.PollInterval {
+		return errors.New("--max-poll-interval must not be less than --poll-interval")
+	}
+
+	if *f.SyncTimeout < 0 {
+		return errors.New("--sync-timeout must not be negative")
+	}
+	return nil
+}
+
 func AddStagingFlags(cmd *cobra.Command) {
 	cmd.Flags().String("auto-delete-duration", "PT12H", "Time until the definition will be deleted, expressed using the ISO-8601 standard.")
 	cmd.Flags().String("data-retention-duration", "PT12H", "The length of time exported data should remain in the staging area., expressed using the ISO-8601 standard.")
@@ -62,7 +106,7 @@ func AddMapDataCardsFlags(cmd *cobra.Command) {
 func GetFlagString(cmd *cobra.Command, flag string) string {
 	s, err := cmd.Flags().GetString(flag)
 	if err != nil {
-		fmt.Printf("error accessing flag %s for command %s: %v", flag, cmd.Name(), err)
+		fmt.Fprintf(os.Stderr, "error accessing flag %s for command %s: %v\n", flag, cmd.Name(), err)
 	}
 	return s
 }
@@ -70,7 +114,7 @@ func GetFlagString(cmd *cobra.Command, flag string) string {
 func GetFlagStringSlice(cmd *cobra.Command, flag string) []string {
 	ss, err := cmd.Flags().GetStringSlice(flag)
 	if err != nil {
-		fmt.Printf("error accessing flag %s for command %s: %v", flag, cmd.Name(), err)
+		fmt.Fprintf(os.Stderr, "error accessing flag %s for command %s: %v\n", flag, cmd.Name(), err)
 	}
 	return ss
 }
@@ -78,7 +122,7 @@ func GetFlagStringSlice(cmd *cobra.Command, flag string) []string {
 func GetFlagBool(cmd *cobra.Command, flag string) bool {
 	b, err := cmd.Flags().GetBool(flag)
 	if err != nil {
-		fmt.Printf("error accessing flag %s for command %s: %v", flag, cmd.Name(), err)
+		fmt.Fprintf(os.Stderr, "error accessing flag %s for command %s: %v\n", flag, cmd.Name(), err)
 	}
 	return b
 }
@@ -86,7 +130,7 @@ func GetFlagBool(cmd *cobra.Command, flag string) bool {
 func GetFlagInt(cmd *cobra.Command, flag string) int {
 	i, err := cmd.Flags().GetInt(flag)
 	if err != nil {
-		fmt.Printf("error accessing flag %s for command %s: %v", flag, cmd.Name(), err)
+		fmt.Fprintf(os.Stderr, "error accessing flag %s for command %s: %v\n", flag, cmd.Name(), err)
 	}
 	return i
 }
@@ -94,7 +138,7 @@ func GetFlagInt(cmd *cobra.Command, flag string) int {
 func GetFlagUint(cmd *cobra.Command, flag string) uint {
 	u, err := cmd.Flags().GetUint(flag)
 	if err != nil {
-		fmt.Printf("error accessing flag %s for command %s: %v", flag, cmd.Name(), err)
+		fmt.Fprintf(os.Stderr, "error accessing flag %s for command %s: %v\n", flag, cmd.Name(), err)
 	}
 	return u
 }
@@ -144,32 +188,49 @@ func (f *PrintFlags) Validate() error {
 
 type ExportFlags struct {
 	StagingFlags             *StagingFlags
+	SyncFlags                *SyncFlags
 	AreSystemTimestampsInUTC *bool
 	Name                     *string
 	Fields                   *string
+	FieldSet                 *string
 	MaxRecords               *uint
 	Filter                   *string
+	KeepDefinition           *bool
+	Reuse                    *bool
+
+	// JobFields are the fields of an export job, added like --fields. They
+	// are not a flag: names and statements may contain ':' and ','.
+	JobFields map[string]string
 }
 
 func NewExportFlags() *ExportFlags {
 	stagingFlags := NewStagingFlags()
+	syncFlags := NewSyncFlags()
 	areSystemTimestampsInUTC := true
 	name := ""
 	fields := ""
+	fieldSet := ""
 	filter := ""
 	maxRecords := uint(0)
+	keepDefinition := false
+	reuse := false
 	return &ExportFlags{
 		StagingFlags:             stagingFlags,
+		SyncFlags:                syncFlags,
 		AreSystemTimestampsInUTC: &areSystemTimestampsInUTC,
 		Name:                     &name,
 		Fields:                   &fields,
+		FieldSet:                 &fieldSet,
 		Filter:                   &filter,
 		MaxRecords:               &maxRecords,
+		KeepDefinition:           &keepDefinition,
+		Reuse:                    &reuse,
 	}
 }
 
 func (f *ExportFlags) AddFlags(cmd *cobra.Command) {
 	f.StagingFlags.AddFlags(cmd)
+	f.SyncFlags.AddFlags(cmd)
 
 	if f.AreSystemTimestampsInUTC != nil {
 		cmd.Flags().BoolVarP(f.AreSystemTimestampsInUTC, "utc", "u", *f.AreSystemTimestampsInUTC, "Whether or not system timestamps will be exported in UTC.")
@@ -179,6 +240,10 @@ func (f *ExportFlags) AddFlags(cmd *cobra.Command) {
 		cmd.Flags().StringVar(f.Fields, "fields", *f.Fields, "List of fields to be included in the export operation.")
 	}
 
+	if f.FieldSet != nil {
+		cmd.Flags().StringVar(f.FieldSet, "field-set", *f.FieldSet, "Name of a field set to export, see 'eloquactl get field-sets'. Fields given by --fields are added to it.")
+	}
+
 	if f.Filter != nil {
 		cmd.Flags().StringVar(f.Filter, "filter", *f.Filter, "The filter parameter uses Eloqua Markup Language to only return certain results.")
 	}
@@ -188,7 +253,15 @@ func (f *ExportFlags) AddFlags(cmd *cobra.Command) {
 	}
 
 	if f.Name != nil {
-		cmd.Flags().StringVarP(f.Name, "name", "n", *f.Name, "The name of the export definition.")
+		cmd.Flags().StringVarP(f.Name, "name", "n", *f.Name, "The name of the export definition. Defaults to a name derived from the entity, fields and filter.")
+	}
+
+	if f.Reuse != nil {
+		cmd.Flags().BoolVar(f.Reuse, "reuse", *f.Reuse, "If true, an existing export definition with the same name is used instead of creating a new one.")
+	}
+
+	if f.KeepDefinition != nil {
+		cmd.Flags().BoolVar(f.KeepDefinition, "keep-definition", *f.KeepDefinition, "If true, the export definition is not deleted when the command is interrupted.")
 	}
 }
 
@@ -198,6 +271,10 @@ func (f *ExportFlags) Validate() error {
 		return err
 	}
 
+	if err := f.SyncFlags.Validate(); err != nil {
+		return err
+	}
+
 	if len(*f.Name) > 100 {
 		// return error
 	}
@@ -206,11 +283,13 @@ func (f *ExportFlags) Validate() error {
 
 type ImportFlags struct {
 	StagingFlags *StagingFlags
+	SyncFlags    *SyncFlags
 
 	Fields                           *string
 	IdentifierFieldName              *string
 	IsSyncTriggeredOnImport          *bool
 	IsUpdatingMultipleMatchedRecords *bool
+	KeepDefinition                   *bool
 	Name                             *string
 	NullIdentifierFieldName          *string
 	SyncActions                      *[]string
@@ -219,20 +298,24 @@ type ImportFlags struct {
 
 func NewImportFlags() *ImportFlags {
 	stagingFlags := NewStagingFlags()
+	syncFlags := NewSyncFlags()
 	fields := ""
 	identifierFieldName := ""
 	isSyncTriggeredOnImport := false
 	isUpdatingMultipleMatchedRecords := false
+	keepDefinition := false
 	name := ""
 	nullIdentifierFieldName := ""
 	syncActions := []string{}
-	updateRule := ""
+	updateRule := "always"
 	return &ImportFlags{
 		StagingFlags:                     stagingFlags,
+		SyncFlags:                        syncFlags,
 		Fields:                           &fields,
 		IdentifierFieldName:              &identifierFieldName,
 		IsSyncTriggeredOnImport:          &isSyncTriggeredOnImport,
 		IsUpdatingMultipleMatchedRecords: &isUpdatingMultipleMatchedRecords,
+		KeepDefinition:                   &keepDefinition,
 		Name:                             &name,
 		NullIdentifierFieldName:          &nullIdentifierFieldName,
 		SyncActions:                      &syncActions,
@@ -241,13 +324,83 @@ func NewImportFlags() *ImportFlags {
 }
 
 func (f *ImportFlags) AddFlags(cmd *cobra.Command) {
+	f.StagingFlags.AddFlags(cmd)
+	f.SyncFlags.AddFlags(cmd)
+
+	if f.Fields != nil {
+		cmd.Flags().StringVar(f.Fields, "fields", *f.Fields, "Mapping of the input columns to fields, e.g. Email:{{Contact.Field(C_EmailAddress)}}. Defaults to the fields named like the columns.")
+	}
 
+	if f.IdentifierFieldName != nil {
+		cmd.Flags().StringVar(f.IdentifierFieldName, "identifier", *f.IdentifierFieldName, "The input column used to match existing records. Defaults to the column of the email address.")
+	}
+
+	if f.IsUpdatingMultipleMatchedRecords != nil {
+		cmd.Flags().BoolVar(f.IsUpdatingMultipleMatchedRecords, "update-multiple", *f.IsUpdatingMultipleMatchedRecords, "If true, all records matching the identifier are updated.")
+	}
+
+	if f.Name != nil {
+		cmd.Flags().StringVarP(f.Name, "name", "n", *f.Name, "The name of the import definition.")
+	}
+
+	if f.KeepDefinition != nil {
+		cmd.Flags().BoolVar(f.KeepDefinition, "keep-definition", *f.KeepDefinition, "If true, the import definition is not deleted when the command is interrupted.")
+	}
+
+	if f.UpdateRule != nil {
+		cmd.Flags().StringVar(f.UpdateRule, "update-rule", *f.UpdateRule, "When existing records are updated. One of: always|ifNewIsNotNull|ifExistingIsNull|useFieldRule.")
+	}
 }
 
 func (f *ImportFlags) Validate() error {
+	if err := f.StagingFlags.Validate(); err != nil {
+		return err
+	}
+
+	if err := f.SyncFlags.Validate(); err != nil {
+		return err
+	}
+
+	if len(*f.Name) > 100 {
+		return errors.New("--name must not be longer than 100 characters")
+	}
+
+	switch *f.UpdateRule {
+	case "always", "ifNewIsNotNull", "ifExistingIsNull", "useFieldRule":
+	default:
+		return fmt.Errorf("invalid update rule %q, expected one of: always|ifNewIsNotNull|ifExistingIsNull|useFieldRule", *f.UpdateRule)
+	}
+
+	if _, err := f.FieldsMap(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// FieldsMap returns the --fields mapping of columns to field statements,
+// empty if the flag is not given.
+func (f *ImportFlags) FieldsMap() (map[string]string, error) {
+	return ParseFields(*f.Fields)
+}
+
+// ParseFields parses a --fields mapping of columns to field statements,
+// e.g. Email:{{Contact.Field(C_EmailAddress)}},Id:{{Contact.Id}}.
+func ParseFields(s string) (map[string]string, error) {
+	fields := map[string]string{}
+	if s == "" {
+		return fields, nil
+	}
+
+	for _, field := range strings.Split(s, ",") {
+		kv := strings.SplitN(field, ":", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" || strings.TrimSpace(kv[1]) == "" {
+			return nil, fmt.Errorf("invalid field %q in --fields, expected COLUMN:STATEMENT", field)
+		}
+		fields[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return fields, nil
+}
+
 /*
 type validateStringFlagFn func(p *string) error
 type validateIntFlagFn func(i *int) error
@@ -257,6 +410,48 @@ func AddStringFlagVar(cmd *cobra.Command, p *string,  name, shorthand, usage str
 }
 */
 
+// AppFlags select the client id and secret of the App Cloud app signing
+// calls, by default those of the OAuth2 client of the context.
+type AppFlags struct {
+	ClientID     *string
+	ClientSecret *string
+}
+
+func NewAppFlags() *AppFlags {
+	clientID := ""
+	clientSecret := ""
+	return &AppFlags{
+		ClientID:     &clientID,
+		ClientSecret: &clientSecret,
+	}
+}
+
+func (f *AppFlags) AddFlags(cmd *cobra.Command) {
+	if f.ClientID != nil {
+		cmd.Flags().StringVar(f.ClientID, "client-id", *f.ClientID, "Client id of the App Cloud app. Defaults to the OAuth2 client of the context.")
+	}
+
+	if f.ClientSecret != nil {
+		cmd.Flags().StringVar(f.ClientSecret, "client-secret", *f.ClientSecret, "Client secret of the App Cloud app. Defaults to the OAuth2 client of the context.")
+	}
+}
+
+func (f *AppFlags) Validate() error {
+	if (*f.ClientID == "") != (*f.ClientSecret == "") {
+		return errors.New("--client-id and --client-secret must be given together")
+	}
+	return nil
+}
+
+// Credentials returns the client id and secret of the flags, or else those
+// returned by context.
+func (f *AppFlags) Credentials(context func() (string, string, error)) (string, string, error) {
+	if *f.ClientID != "" {
+		return *f.ClientID, *f.ClientSecret, nil
+	}
+	return context()
+}
+
 type FileNameFlags struct {
 	FileNames *[]string
 	Recursive *bool
@@ -289,6 +484,55 @@ func (f *FileNameFlags) Validate() error {
 	return nil
 }
 
+// Files returns the files given by --filename. Directories are expanded to
+// the files with one of the extensions, recursively with --recursive.
+// "-" stands for stdin and is returned as is.
+func (f *FileNameFlags) Files(extensions ...string) ([]string, error) {
+	var files []string
+	for _, name := range *f.FileNames {
+		if name == "-" {
+			files = append(files, name)
+			continue
+		}
+
+		fi, err := os.Stat(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !fi.IsDir() {
+			files = append(files, name)
+			continue
+		}
+
+		err = filepath.Walk(name, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				if path != name && !*f.Recursive {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			for _, ext := range extensions {
+				if strings.EqualFold(filepath.Ext(path), ext) {
+					files = append(files, path)
+					break
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
 type ListFlags struct {
 	Count         *int
 	Depth         *string
@@ -335,3 +579,20 @@ func (f *ListFlags) AddFlags(cmd *cobra.Command) {
 		cmd.Flags().StringVar(f.Search, "filter", *f.Search, "Specifies the search criteria used to retrieve entities.")
 	}
 }
+
+func (f *ListFlags) Validate() error {
+	if *f.Count < 1 || *f.Count > 1000 {
+		return errors.New("--count must be between 1 and 1000")
+	}
+
+	switch *f.Depth {
+	case "minimal", "partial", "complete":
+	default:
+		return fmt.Errorf("invalid --depth %q, expected one of: minimal|partial|complete", *f.Depth)
+	}
+
+	if *f.Page < 1 {
+		return errors.New("--page must be at least 1")
+	}
+	return nil
+}
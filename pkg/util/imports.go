@@ -0,0 +1,300 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/printers"
+)
+
+const (
+	// uploadBatchSize is the number of records uploaded per request, well
+	// below the 32 MB limit of the request size
+	uploadBatchSize = 5000
+	cleanupTimeout  = 30 * time.Second
+
+	emailAddressStatement = "{{Contact.Field(C_EmailAddress)}}"
+)
+
+var instanceRegex = regexp.MustCompile(`^[0-9a-fA-F]{32}$`)
+
+// ValidateInstance checks the id of an App Cloud service instance given by
+// --instance: 32 hex digits, with or without the dashes of a GUID.
+func ValidateInstance(id string) error {
+	if id == "" {
+		return errors.New("--instance is required")
+	}
+
+	if !instanceRegex.MatchString(bulkapi.InstanceID(id)) {
+		return fmt.Errorf("invalid instance id %q", id)
+	}
+	return nil
+}
+
+// RunImport creates the import definition under path, e.g. /contacts/imports,
+// uploads the records and syncs them. It returns the result of every record,
+// in order. The definition is deleted when the command is interrupted, unless
+// keepDefinition is set.
+func RunImport(ctx context.Context, client *bulk.BulkClient, path string, i *bulkapi.Import, records []map[string]string, sf *SyncFlags, keepDefinition bool) (results []bulkapi.ImportResult, err error) {
+	identifier := i.IdentifierFieldName
+
+	i, err = bulkapi.CreateImport(ctx, client, path, i)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create import definition: %w", err)
+	}
+
+	defer func() {
+		if ctx.Err() == nil {
+			return
+		}
+		if !keepDefinition {
+			deleteDefinition(client, i.Uri, "import")
+		}
+		err = &InterruptedError{}
+	}()
+
+	// columns that are not fields of the definition are not uploaded
+	data := make([]map[string]string, len(records))
+	for n, record := range records {
+		data[n] = make(map[string]string, len(i.Fields))
+		for field := range i.Fields {
+			data[n][field] = record[field]
+		}
+	}
+
+	progress := NewProgress(i.Uri)
+	progress.Unit = "rows uploaded"
+	for start := 0; start < len(records); start += uploadBatchSize {
+		end := start + uploadBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+
+		progress.Update("uploading", start)
+		if err := bulkapi.UploadData(ctx, client, i.Uri, data[start:end]); err != nil {
+			progress.Done()
+			return nil, fmt.Errorf("failed to upload data: %w", err)
+		}
+	}
+	progress.Update("uploaded", len(records))
+	progress.Done()
+
+	sync, err := client.Syncs.Create(ctx, &bulk.Sync{SyncedInstanceURI: i.Uri})
+	if err != nil {
+		return nil, err
+	}
+
+	syncId, err := bulkapi.SyncID(sync.Uri)
+	if err != nil {
+		return nil, err
+	}
+
+	progress = NewProgress(sync.Uri)
+	progress.Unit = ""
+	sync, err = WaitSync(ctx, client, sync, sf, progress)
+	progress.Done()
+	if err != nil {
+		return nil, err
+	}
+
+	if sync.Status != bulkapi.SyncSuccess {
+		if err := PrintSyncLogs(ctx, client, syncId); err != nil {
+			return nil, err
+		}
+
+		if sync.Status == bulkapi.SyncError {
+			return nil, &SyncError{Uri: sync.Uri, Err: errors.New("failed")}
+		}
+	}
+
+	var rejects []bulkapi.SyncReject
+	if sync.Status == bulkapi.SyncWarning {
+		rejects, err = bulkapi.SyncRejects(ctx, client, syncId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rejected records: %w", err)
+		}
+	}
+
+	return importResults(records, identifier, rejects), nil
+}
+
+// Rejects returns the results of the rows an import rejected.
+func Rejects(results []bulkapi.ImportResult) []bulkapi.ImportResult {
+	var rejects []bulkapi.ImportResult
+	for _, r := range results {
+		if r.Status == bulkapi.ResultRejected {
+			rejects = append(rejects, r)
+		}
+	}
+	return rejects
+}
+
+// ImportError returns a PartialError when some rows were rejected.
+func ImportError(results []bulkapi.ImportResult) error {
+	if rejected := len(Rejects(results)); rejected > 0 {
+		return &PartialError{Err: fmt.Errorf("%d of %d contacts were rejected", rejected, len(results))}
+	}
+	return nil
+}
+
+// PrintImportResults prints the result of every row, then the summary line
+// made by summary from the number of imported and of all rows to stderr.
+// It returns the ImportError of the results.
+func PrintImportResults(results []bulkapi.ImportResult, pf *PrintFlags, summary func(imported, total int) string) error {
+	printer, err := pf.ToPrinter()
+	if err != nil {
+		return err
+	}
+	w := printers.NewTabWriter(os.Stdout)
+	if err := printer.PrintResource(results, w); err != nil {
+		return err
+	}
+	w.Flush()
+
+	imported := len(results) - len(Rejects(results))
+	fmt.Fprintln(os.Stderr, summary(imported, len(results)))
+	return ImportError(results)
+}
+
+// ContactImportFields returns the fields of a contact import of the columns
+// and the identifier column. Unless --fields is given, every column must be
+// named like a contact field, by its name or internal name.
+func ContactImportFields(ctx context.Context, md *bulkapi.Metadata, columns []string, f *ImportFlags) (map[string]string, string, error) {
+	fields, err := f.FieldsMap()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(fields) == 0 {
+		r, err := md.ContactFields(ctx)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list contact fields: %w", err)
+		}
+
+		for _, column := range columns {
+			for _, field := range r.Items {
+				if isField(column, field.Name, field.InternalName) {
+					fields[column] = field.Statement
+					break
+				}
+			}
+			if _, ok := fields[column]; !ok {
+				return nil, "", &ValidationError{Err: fmt.Errorf("column %q is not a contact field, map the columns with --fields", column)}
+			}
+		}
+	}
+
+	identifier := *f.IdentifierFieldName
+	if identifier == "" {
+		for column, statement := range fields {
+			if statement == emailAddressStatement {
+				identifier = column
+			}
+		}
+	}
+
+	if identifier == "" {
+		return nil, "", ValidationErrorf("there is no email address column, set the column to match contacts by with --identifier")
+	}
+	if _, ok := fields[identifier]; !ok {
+		return nil, "", ValidationErrorf("identifier %q is not an imported column", identifier)
+	}
+	return fields, identifier, nil
+}
+
+// ContactIdentifier returns the column contacts are matched by and its
+// field statement. The column is --identifier, or else the column of the
+// email address, EmailAddress when there are no columns.
+func ContactIdentifier(ctx context.Context, md *bulkapi.Metadata, columns []string, f *ImportFlags) (string, string, error) {
+	fields, err := f.FieldsMap()
+	if err != nil {
+		return "", "", err
+	}
+
+	column := *f.IdentifierFieldName
+	if column == "" && len(columns) == 0 {
+		column = "EmailAddress"
+		if _, ok := fields[column]; !ok {
+			return column, emailAddressStatement, nil
+		}
+	}
+
+	if statement, ok := fields[column]; ok {
+		return column, statement, nil
+	}
+
+	r, err := md.ContactFields(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list contact fields: %w", err)
+	}
+
+	if column != "" {
+		for _, field := range r.Items {
+			if isField(column, field.Name, field.InternalName) {
+				return column, field.Statement, nil
+			}
+		}
+		return "", "", ValidationErrorf("identifier %q is not a contact field, map it with --fields", column)
+	}
+
+	for _, c := range columns {
+		if fields[c] == emailAddressStatement {
+			return c, emailAddressStatement, nil
+		}
+		for _, field := range r.Items {
+			if field.Statement == emailAddressStatement && isField(c, field.Name, field.InternalName) {
+				return c, emailAddressStatement, nil
+			}
+		}
+	}
+	return "", "", ValidationErrorf("there is no email address column, set the column to match contacts by with --identifier")
+}
+
+// isField reports whether a column is named like a field, by its name, e.g.
+// Email Address or EmailAddress, or its internal name, e.g. C_EmailAddress.
+func isField(column, name, internalName string) bool {
+	column = strings.ReplaceAll(column, " ", "")
+	return strings.EqualFold(column, strings.ReplaceAll(name, " ", "")) || strings.EqualFold(column, internalName)
+}
+
+// importResults returns the result of every record. The record index of a
+// reject is the position of the record in the uploaded data, from 1.
+func importResults(records []map[string]string, identifier string, rejects []bulkapi.SyncReject) []bulkapi.ImportResult {
+	rejected := make(map[int]bulkapi.SyncReject, len(rejects))
+	for _, r := range rejects {
+		rejected[r.RecordIndex] = r
+	}
+
+	results := make([]bulkapi.ImportResult, len(records))
+	for n, record := range records {
+		row := n + 1
+		results[n] = bulkapi.ImportResult{Row: row, Identifier: record[identifier], Status: bulkapi.ResultImported}
+		if r, ok := rejected[row]; ok {
+			results[n].Status = bulkapi.ResultRejected
+			results[n].Message = r.Message
+			if len(r.InvalidFields) > 0 {
+				results[n].Message += " (" + strings.Join(r.InvalidFields, ", ") + ")"
+			}
+		}
+	}
+	return results
+}
+
+// deleteDefinition deletes the import or export definition with its own
+// context, as the command context may be canceled already.
+func deleteDefinition(client *bulk.BulkClient, uri, kind string) {
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+
+	fmt.Fprintf(os.Stderr, "Deleting %s definition %s\n", kind, uri)
+	if err := bulkapi.DeleteDefinition(ctx, client, uri); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to delete %s definition %s: %v\n", kind, uri, err)
+	}
+}
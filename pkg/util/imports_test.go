@@ -0,0 +1,30 @@
+package util
+
+import "testing"
+
+func TestValidateInstance(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		wantErr bool
+	}{
+		{name: "GUID", id: "a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f"},
+		{name: "without dashes", id: "a12d53dd1a2b4c5d8e9f0a1b2c3d4e5f"},
+		{name: "upper case", id: "A12D53DD-1A2B-4C5D-8E9F-0A1B2C3D4E5F"},
+		{name: "empty", id: "", wantErr: true},
+		{name: "too short", id: "a12d53dd-1a2b-4c5d-8e9f", wantErr: true},
+		{name: "too long", id: "a12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f00", wantErr: true},
+		{name: "only dashes", id: "--------", wantErr: true},
+		{name: "not hex", id: "g12d53dd-1a2b-4c5d-8e9f-0a1b2c3d4e5f", wantErr: true},
+		{name: "markup", id: "a12d53dd)}}{{Contact.Id", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateInstance(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateInstance(%q) = %v, want error %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
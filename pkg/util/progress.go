@@ -0,0 +1,72 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Progress reports the state of a long running operation on stderr.
+// On a terminal the status line is redrawn in place, otherwise a line is
+// written only when the status changes, to keep logs readable.
+type Progress struct {
+	Name string
+	// Unit describes the rows, e.g. "rows downloaded". Rows are not
+	// reported when it is empty.
+	Unit string
+
+	start  time.Time
+	tty    bool
+	status string
+	rows   int
+
+	// open is set while a redrawn line is not terminated, dirty when the
+	// last update was not written
+	open  bool
+	dirty bool
+}
+
+func NewProgress(name string) *Progress {
+	return &Progress{
+		Name:  name,
+		Unit:  "rows downloaded",
+		start: time.Now(),
+		tty:   isTerminal(os.Stderr),
+	}
+}
+
+// Update sets the status and the number of rows processed so far.
+func (p *Progress) Update(status string, rows int) {
+	changed := status != p.status
+	p.status = status
+	p.rows = rows
+
+	if p.tty {
+		fmt.Fprintf(os.Stderr, "\r\033[K%s", p.line())
+		p.open = true
+	} else if changed {
+		fmt.Fprintln(os.Stderr, p.line())
+		p.dirty = false
+	} else {
+		p.dirty = true
+	}
+}
+
+// Done ends the status line, making sure the last update is written.
+func (p *Progress) Done() {
+	if p.open {
+		fmt.Fprintf(os.Stderr, "\r\033[K%s\n", p.line())
+		p.open = false
+	} else if p.dirty {
+		fmt.Fprintln(os.Stderr, p.line())
+		p.dirty = false
+	}
+}
+
+func (p *Progress) line() string {
+	elapsed := time.Since(p.start).Round(time.Second)
+	if p.Unit == "" {
+		return fmt.Sprintf("%s: %s, %v elapsed", p.Name, p.status, elapsed)
+	}
+	return fmt.Sprintf("%s: %s, %v elapsed, %d %s", p.Name, p.status, elapsed, p.rows, p.Unit)
+}
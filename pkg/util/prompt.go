@@ -0,0 +1,88 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+var stdin = bufio.NewReader(os.Stdin)
+
+// Prompt asks the user for a value on stderr and reads it from stdin.
+// If def is not empty, it is offered as the default value.
+func Prompt(label, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(os.Stderr, "%s [%s]: ", label, def)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: ", label)
+	}
+
+	s, err := stdin.ReadString('\n')
+	if err != nil && s == "" {
+		return "", err
+	}
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		s = def
+	}
+	return s, nil
+}
+
+// PromptSecret asks the user for a secret, without echoing it when stdin is a terminal.
+func PromptSecret(label string) (string, error) {
+	fmt.Fprintf(os.Stderr, "%s: ", label)
+
+	if isTerminal(os.Stdin) {
+		if err := stty("-echo"); err == nil {
+			defer func() {
+				stty("echo")
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+
+	s, err := stdin.ReadString('\n')
+	if err != nil && s == "" {
+		return "", err
+	}
+	return strings.TrimRight(s, "\r\n"), nil
+}
+
+// Confirm asks the user a yes or no question on stderr. The answer is no
+// without asking when stdin is not a terminal, e.g. in scripts.
+func Confirm(question string) (bool, error) {
+	if !isTerminal(os.Stdin) {
+		return false, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+	s, err := stdin.ReadString('\n')
+	if err == io.EOF && s == "" {
+		// no answer
+		fmt.Fprintln(os.Stderr)
+		return false, nil
+	} else if err != nil && s == "" {
+		return false, err
+	}
+
+	s = strings.ToLower(strings.TrimSpace(s))
+	return s == "y" || s == "yes", nil
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+func stty(arg string) error {
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
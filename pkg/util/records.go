@@ -0,0 +1,158 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+// ReadRecords reads the records to import from a file, "-" being stdin.
+// The file is CSV with a header row, a JSON array of objects or newline
+// delimited JSON objects. It returns the columns, in the order of the CSV
+// header or sorted for JSON, and the records keyed by column.
+func ReadRecords(file string) ([]string, []map[string]string, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var columns []string
+	var records []map[string]string
+	switch trimmed := bytes.TrimSpace(data); {
+	case len(trimmed) == 0:
+	case trimmed[0] == '[':
+		columns, records, err = readJSONRecords(trimmed)
+	case trimmed[0] == '{':
+		columns, records, err = readNdjRecords(trimmed)
+	default:
+		columns, records, err = readCSVRecords(data)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return columns, records, nil
+}
+
+// ReadRecordFiles reads the records of the files, in order. The columns are
+// those of all files, in the order they are first found.
+func ReadRecordFiles(files []string) ([]string, []map[string]string, error) {
+	var columns []string
+	var records []map[string]string
+	seen := map[string]bool{}
+	for _, file := range files {
+		c, r, err := ReadRecords(file)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, column := range c {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+		records = append(records, r...)
+	}
+	return columns, records, nil
+}
+
+func readCSVRecords(data []byte) ([]string, []map[string]string, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	columns, err := r.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var records []map[string]string
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		record := make(map[string]string, len(columns))
+		for i, column := range columns {
+			record[column] = row[i]
+		}
+		records = append(records, record)
+	}
+	return columns, records, nil
+}
+
+func readJSONRecords(data []byte) ([]string, []map[string]string, error) {
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, nil, err
+	}
+	return JSONRecords(objects)
+}
+
+func readNdjRecords(data []byte) ([]string, []map[string]string, error) {
+	var objects []map[string]interface{}
+	s := bufio.NewScanner(bytes.NewReader(data))
+	s.Buffer(nil, 1024*1024)
+	for line := 1; s.Scan(); line++ {
+		if len(bytes.TrimSpace(s.Bytes())) == 0 {
+			continue
+		}
+
+		var o map[string]interface{}
+		if err := json.Unmarshal(s.Bytes(), &o); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		objects = append(objects, o)
+	}
+	if err := s.Err(); err != nil {
+		return nil, nil, err
+	}
+	return JSONRecords(objects)
+}
+
+// JSONRecords converts JSON objects to records, values other than strings
+// are imported as their JSON text, null as an empty value.
+func JSONRecords(objects []map[string]interface{}) ([]string, []map[string]string, error) {
+	seen := map[string]bool{}
+	var columns []string
+	records := make([]map[string]string, 0, len(objects))
+
+	for _, o := range objects {
+		record := make(map[string]string, len(o))
+		for k, v := range o {
+			if !seen[k] {
+				seen[k] = true
+				columns = append(columns, k)
+			}
+
+			switch v := v.(type) {
+			case nil:
+				record[k] = ""
+			case string:
+				record[k] = v
+			default:
+				b, err := json.Marshal(v)
+				if err != nil {
+					return nil, nil, err
+				}
+				record[k] = string(b)
+			}
+		}
+		records = append(records, record)
+	}
+
+	sort.Strings(columns)
+	return columns, records, nil
+}
@@ -0,0 +1,49 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+)
+
+// SegmentContacts exports the contacts of a segment, e.g. to import them to
+// an App Cloud service instance. The records have a single column with the
+// given name and field statement.
+func SegmentContacts(ctx context.Context, client *bulk.BulkClient, segment int, column, statement string, sf *SyncFlags, keepDefinition bool) ([]map[string]string, error) {
+	records, err := ExportContacts(ctx, client, &bulk.Export{
+		Name:   fmt.Sprintf("eloquactl segment %d", segment),
+		Fields: map[string]string{column: statement},
+		Filter: fmt.Sprintf("EXISTS('{{ContactSegment[%d]}}')", segment),
+	}, sf, keepDefinition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export segment %d: %w", segment, err)
+	}
+	return records, nil
+}
+
+// ExportContacts exports contacts and returns them as records keyed by the
+// fields of the export, by RunExport. The records exported before a failure
+// or an interrupt are returned with the error. When the sync finishes with
+// warnings, its logs are printed and all records are returned.
+func ExportContacts(ctx context.Context, client *bulk.BulkClient, e *bulk.Export, sf *SyncFlags, keepDefinition bool) ([]map[string]string, error) {
+	e, err := client.Contacts.CreateExport(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []map[string]string
+	_, err = RunExport(ctx, client, e.Uri, sf, keepDefinition, func(items []bulk.Item) error {
+		for _, item := range items {
+			records = append(records, map[string]string(item))
+		}
+		return nil
+	})
+
+	var pe *PartialError
+	if errors.As(err, &pe) {
+		return records, nil
+	}
+	return records, err
+}
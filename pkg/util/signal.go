@@ -0,0 +1,37 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// InterruptContext returns a context that is canceled on SIGINT or SIGTERM,
+// so that commands can stop and clean up. A second signal exits immediately.
+func InterruptContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := make(chan os.Signal, 2)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-c:
+			fmt.Fprintln(os.Stderr, "\nInterrupted, cleaning up. Press Ctrl-C again to exit immediately.")
+			cancel()
+		case <-ctx.Done():
+			signal.Stop(c)
+			return
+		}
+
+		<-c
+		os.Exit(130)
+	}()
+
+	return ctx, func() {
+		signal.Stop(c)
+		cancel()
+	}
+}
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/elqx/eloqua-go/eloqua/bulk"
+	"github.com/elqx/eloquactl/pkg/bulkapi"
+	"github.com/elqx/eloquactl/pkg/printers"
+)
+
+// WaitSync polls the sync status until the sync is done. The interval between
+// polls grows by half on every poll, up to the max poll interval.
+func WaitSync(ctx context.Context, client *bulk.BulkClient, sync *bulk.Sync, sf *SyncFlags, progress *Progress) (*bulk.Sync, error) {
+	syncId, err := bulkapi.SyncID(sync.Uri)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeout <-chan time.Time
+	if *sf.SyncTimeout > 0 {
+		timer := time.NewTimer(*sf.SyncTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	interval := *sf.PollInterval
+	for !bulkapi.IsDone(sync.Status) {
+		progress.Update(sync.Status, 0)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timeout:
+			return nil, &SyncError{Uri: sync.Uri, Err: fmt.Errorf("did not finish within %v, last status: %s", *sf.SyncTimeout, sync.Status)}
+		case <-time.After(interval):
+		}
+
+		s, err := client.Syncs.Get(ctx, syncId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check sync status: %w", err)
+		}
+		sync = s
+
+		if interval = interval * 3 / 2; interval > *sf.MaxPollInterval {
+			interval = *sf.MaxPollInterval
+		}
+	}
+
+	progress.Update(sync.Status, 0)
+	return sync, nil
+}
+
+// PrintSyncLogs prints the sync log entries to stderr.
+func PrintSyncLogs(ctx context.Context, client *bulk.BulkClient, syncId int) error {
+	logs, err := bulkapi.SyncLogs(ctx, client, syncId)
+	if err != nil {
+		return fmt.Errorf("failed to get sync logs: %w", err)
+	}
+
+	w := printers.NewTabWriter(os.Stderr)
+	fmt.Fprintln(w, "SEVERITY\tCOUNT\tSTATUS CODE\tMESSAGE")
+	for _, l := range logs {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", l.Severity, l.Count, l.StatusCode, l.Message)
+	}
+	return w.Flush()
+}